@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/GiannisPettas/ember2D/internal/engine/loader"
 	"github.com/gorilla/websocket"
 )
 
@@ -20,6 +21,9 @@ type serverMessage struct {
 	Data any    `json:"data,omitempty"`
 }
 
+// blocks lists the conditions and actions rule cards can use.
+var blocks = loader.DefaultRegistry()
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -71,6 +75,15 @@ func handleWS(w http.ResponseWriter, r *http.Request) {
 		}
 
 		switch in.Action {
+		case "list_blocks":
+			// Available rule blocks and their parameter schemas
+			send <- serverMessage{
+				Type: "blocks",
+				Data: map[string]any{
+					"conditions": blocks.Conditions(),
+					"actions":    blocks.Actions(),
+				},
+			}
 		case "ping":
 			send <- serverMessage{
 				Type: "pong",
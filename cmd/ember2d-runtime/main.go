@@ -1,11 +1,15 @@
 package main
 
 import (
+	"flag"
 	"image/color"
 	"log"
+	"time"
 
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
 	"github.com/GiannisPettas/ember2D/internal/engine/components"
 	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+	"github.com/GiannisPettas/ember2D/internal/engine/loader"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 )
@@ -16,62 +20,67 @@ var (
 	positions  *components.ComponentManager[components.Position]
 	velocities *components.ComponentManager[components.Velocity]
 	displays   *components.ComponentManager[components.Display]
+	dispatcher *behavior.Dispatcher
+)
+
+// System queries
+var (
+	movers    *components.Query2[components.Position, components.Velocity]
+	bouncers  *components.Query2[components.Position, components.Velocity]
+	players   *components.Query2[components.Position, components.Display]
+	drawables *components.Query2[components.Position, components.Display]
 )
 
 type Game struct{}
 
 func (g *Game) Update() error {
+	// Rule cards: route queued events to behaviors
+	dispatcher.Update(time.Second / time.Duration(ebiten.TPS()))
+
 	// Movement system: move entities based on velocity
-	velocities.Each(func(e entity.Entity, vel *components.Velocity) {
-		if pos := positions.Get(e); pos != nil {
-			pos.X += vel.X
-			pos.Y += vel.Y
-		}
+	movers.Each(func(e entity.Entity, pos *components.Position, vel *components.Velocity) {
+		pos.X += vel.X
+		pos.Y += vel.Y
 	})
 
 	// Bounce enemies off screen edges
-	for _, e := range world.Tags().GetEntitiesByTag("enemy") {
-		if pos := positions.Get(e); pos != nil {
-			if vel := velocities.Get(e); vel != nil {
-				if pos.Y > 440 || pos.Y < 0 {
-					vel.Y = -vel.Y
-				}
-				if pos.X > 600 || pos.X < 0 {
-					vel.X = -vel.X
-				}
-			}
+	bouncers.Each(func(e entity.Entity, pos *components.Position, vel *components.Velocity) {
+		if pos.Y > 440 || pos.Y < 0 {
+			vel.Y = -vel.Y
 		}
-	}
+		if pos.X > 600 || pos.X < 0 {
+			vel.X = -vel.X
+		}
+	})
 
 	// Player movement with arrow keys
-	for _, e := range world.Tags().GetEntitiesByTag("player") {
-		if pos := positions.Get(e); pos != nil {
-			speed := 3.0
-			if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
-				pos.Y -= speed
-			}
-			if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
-				pos.Y += speed
-			}
-			if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
-				pos.X -= speed
-			}
-			if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
-				pos.X += speed
-			}
+	players.Each(func(e entity.Entity, pos *components.Position, _ *components.Display) {
+		speed := 3.0
+		if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+			pos.Y -= speed
 		}
-	}
+		if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+			pos.Y += speed
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+			pos.X -= speed
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+			pos.X += speed
+		}
+	})
+
+	// Free entities destroyed this frame
+	world.Cleanup()
 
 	return nil
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
 	// Render system: draw all entities with Position + Display
-	displays.Each(func(e entity.Entity, d *components.Display) {
-		if pos := positions.Get(e); pos != nil {
-			clr := color.RGBA{R: d.R, G: d.G, B: d.B, A: 255}
-			ebitenutil.DrawRect(screen, pos.X, pos.Y, d.Width, d.Height, clr)
-		}
+	drawables.Each(func(e entity.Entity, pos *components.Position, d *components.Display) {
+		clr := color.RGBA{R: d.R, G: d.G, B: d.B, A: 255}
+		ebitenutil.DrawRect(screen, pos.X, pos.Y, d.Width, d.Height, clr)
 	})
 
 	// Debug info
@@ -83,30 +92,36 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 func main() {
+	levelPath := flag.String("level", "config/example_level.json", "scene file to load")
+	rulesPath := flag.String("rules", "config/example_rules.json", "rule file to load")
+	flag.Parse()
+
 	// Initialize world and component managers
 	world = entity.NewWorld()
-	positions = components.NewComponentManager[components.Position]()
-	velocities = components.NewComponentManager[components.Velocity]()
-	displays = components.NewComponentManager[components.Display]()
-
-	// Create player (blue square)
-	player := world.CreateEntity("player")
-	positions.Add(player, components.Position{X: 300, Y: 220})
-	displays.Add(player, components.Display{Width: 30, Height: 30, R: 50, G: 100, B: 255})
-
-	// Create enemies (red squares that bounce around)
-	for i := 0; i < 5; i++ {
-		enemy := world.CreateEntity("enemy")
-		positions.Add(enemy, components.Position{
-			X: float64(80 + i*100),
-			Y: float64(50 + i*60),
-		})
-		velocities.Add(enemy, components.Velocity{
-			X: float64(1 + i),
-			Y: float64(2 - i),
-		})
-		displays.Add(enemy, components.Display{Width: 20, Height: 20, R: 255, G: 50, B: 50})
+	components.RegisterBuiltins(world)
+	positions = components.Lookup[components.Position](world, "position")
+	velocities = components.Lookup[components.Velocity](world, "velocity")
+	displays = components.Lookup[components.Display](world, "display")
+
+	movers = components.NewQuery2(positions, velocities)
+	bouncers = components.NewQuery2(positions, velocities).WithTags(world.Tags(), "enemy")
+	players = components.NewQuery2(positions, displays).WithTags(world.Tags(), "player")
+	drawables = components.NewQuery2(positions, displays)
+
+	// Load level entities (player + bouncing enemies)
+	if _, err := loader.LoadSceneFile(*levelPath, world); err != nil {
+		log.Fatalf("loading level: %v", err)
+	}
+
+	// Compile rule cards into behaviors
+	behaviors, err := loader.LoadRulesFile(*rulesPath, loader.DefaultRegistry())
+	if err != nil {
+		log.Fatalf("loading rules: %v", err)
 	}
+	dispatcher = behavior.NewDispatcher(world, behaviors)
+	dispatcher.EmitEntityEvents()
+	dispatcher.EmitTagEvents()
+	dispatcher.Start()
 
 	// Run game
 	ebiten.SetWindowTitle("ember2D Runtime")
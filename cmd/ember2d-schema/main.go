@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/components"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+	"github.com/GiannisPettas/ember2D/internal/engine/loader"
+	"github.com/GiannisPettas/ember2D/internal/engine/schema"
+	jsonutil "github.com/GiannisPettas/ember2D/internal/util/json"
+)
+
+// writeSchema writes doc as indented JSON to dir/name.
+func writeSchema(dir, name string, doc schema.Schema) {
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := jsonutil.EncodeIndent(f, doc); err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %s", path)
+}
+
+func main() {
+	outDir := flag.String("out", "schema", "directory to write the schema files to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	// Rule blocks: everything the runtime's registry knows
+	writeSchema(*outDir, "rules.schema.json", schema.Rules(loader.DefaultRegistry()))
+
+	// Scene components: the same set the runtime registers
+	world := entity.NewWorld()
+	components.RegisterBuiltins(world)
+	scene, err := schema.Scene(world)
+	if err != nil {
+		log.Fatal(err)
+	}
+	writeSchema(*outDir, "level.schema.json", scene)
+}
@@ -2,11 +2,13 @@ module github.com/GiannisPettas/ember2D
 
 go 1.22
 
-require github.com/hajimehoshi/ebiten/v2 v2.6.0
+require (
+	github.com/gorilla/websocket v1.5.3
+	github.com/hajimehoshi/ebiten/v2 v2.6.0
+)
 
 require (
 	github.com/ebitengine/purego v0.5.0 // indirect
-	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/jezek/xgb v1.1.0 // indirect
 	golang.org/x/exp/shiny v0.0.0-20230817173708-d852ddb80c63 // indirect
 	golang.org/x/image v0.12.0 // indirect
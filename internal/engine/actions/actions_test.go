@@ -0,0 +1,312 @@
+package actions
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/components"
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+	"github.com/GiannisPettas/ember2D/internal/engine/registry"
+)
+
+type Health struct {
+	Current int `json:"current"`
+	Max     int `json:"max"`
+}
+
+// actionWorld returns a world with a health component and a "hit" event of
+// the player (A) striking an enemy (B).
+func actionWorld() (*entity.World, *components.ComponentManager[Health], core.Event) {
+	world := entity.NewWorld()
+	healths := components.Register(world, "health",
+		components.NumberField("current", func(h *Health) *int { return &h.Current }),
+		components.NumberField("max", func(h *Health) *int { return &h.Max }),
+	)
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	healths.Add(player, Health{Current: 10, Max: 10})
+	healths.Add(enemy, Health{Current: 5, Max: 5})
+	ev := core.Event{Type: "hit", A: player, B: enemy, Payload: map[string]any{"damage": 3.0}}
+	return world, healths, ev
+}
+
+// newAction builds an action through the built-in registry, as a rule file
+// would.
+func newAction(t *testing.T, name string, params map[string]any) behavior.Action {
+	t.Helper()
+	r := registry.New()
+	Register(r)
+	act, err := r.NewAction(name, params)
+	if err != nil {
+		t.Fatalf("NewAction(%q) failed: %v", name, err)
+	}
+	return act
+}
+
+// ============================================
+// Entity Tests
+// ============================================
+
+func TestSpawnEntity(t *testing.T) {
+	world, healths, ev := actionWorld()
+	world.DefinePrefab("bullet", entity.Prefab{
+		Tags:       []string{"projectile"},
+		Components: map[string]json.RawMessage{"health": json.RawMessage(`{"current": 1, "max": 1}`)},
+	})
+
+	act := newAction(t, "spawn_entity", map[string]any{"prefab": "bullet", "tags": "fast, player.owned"})
+	if err := act.Execute(core.NewContext(world, ev)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	spawned := world.Tags().GetEntitiesByTag("projectile")
+	if len(spawned) != 1 {
+		t.Fatalf("Expected 1 projectile, got %d", len(spawned))
+	}
+	e := spawned[0]
+	if !world.Tags().HasTag(e, "fast") || !world.Tags().HasTag(e, "player.owned") {
+		t.Errorf("Extra tags missing: %v", world.Tags().GetTags(e))
+	}
+	if hp := healths.Get(e); hp == nil || hp.Current != 1 {
+		t.Errorf("Prefab component not applied: %v", hp)
+	}
+
+	unknown := &SpawnEntity{Prefab: "rocket"}
+	if err := unknown.Execute(core.NewContext(world, ev)); err == nil {
+		t.Error("Spawning an unknown prefab should fail")
+	}
+}
+
+func TestDestroyEntity(t *testing.T) {
+	world, _, ev := actionWorld()
+
+	act := newAction(t, "destroy_entity", map[string]any{"target": "B"})
+	if err := act.Execute(core.NewContext(world, ev)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if world.IsAlive(ev.B) || !world.IsAlive(ev.A) {
+		t.Error("Expected only B to be destroyed")
+	}
+
+	if err := act.Execute(core.NewContext(world, core.Event{Type: "tick"})); err == nil {
+		t.Error("Destroying a missing participant should fail")
+	}
+}
+
+func TestDestroyEntitiesByTag(t *testing.T) {
+	world, _, ev := actionWorld()
+	extra := world.CreateEntity("enemy")
+
+	act := newAction(t, "destroy_entity", map[string]any{"tag": "enemy"})
+	if err := act.Execute(core.NewContext(world, core.Event{Type: "tick"})); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if world.IsAlive(ev.B) || world.IsAlive(extra) || !world.IsAlive(ev.A) {
+		t.Error("Expected every enemy, and only enemies, to be destroyed")
+	}
+}
+
+func TestAddRemoveTag(t *testing.T) {
+	world, _, ev := actionWorld()
+	ctx := core.NewContext(world, ev)
+
+	if err := newAction(t, "add_tag", map[string]any{"target": "B", "tag": "stunned"}).Execute(ctx); err != nil {
+		t.Fatalf("add_tag failed: %v", err)
+	}
+	if !world.Tags().HasTag(ev.B, "stunned") {
+		t.Error("B should be stunned")
+	}
+
+	if err := newAction(t, "remove_tag", map[string]any{"tag": "player"}).Execute(ctx); err != nil {
+		t.Fatalf("remove_tag failed: %v", err)
+	}
+	if world.Tags().HasTag(ev.A, "player") {
+		t.Error("A (the default target) should have lost its tag")
+	}
+}
+
+// ============================================
+// Field Tests
+// ============================================
+
+func TestSetField(t *testing.T) {
+	world, healths, ev := actionWorld()
+
+	act := newAction(t, "set_field", map[string]any{"target": "B", "component": "Health", "field": "current", "value": 2.0})
+	if err := act.Execute(core.NewContext(world, ev)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if hp := healths.Get(ev.B); hp.Current != 2 {
+		t.Errorf("Expected B health 2, got %d", hp.Current)
+	}
+
+	bad := &SetField{Target: TargetB, Component: "health", Field: "current", Value: 2.5}
+	if err := bad.Execute(core.NewContext(world, ev)); err == nil {
+		t.Error("Writing 2.5 into an int field should fail")
+	}
+}
+
+type Status struct {
+	Mood  string
+	Angry bool
+}
+
+func TestSetFieldStringAndBool(t *testing.T) {
+	world, _, ev := actionWorld()
+	statuses := components.Register(world, "status",
+		components.StringField("mood", func(s *Status) *string { return &s.Mood }),
+		components.BoolField("angry", func(s *Status) *bool { return &s.Angry }),
+	)
+	statuses.Add(ev.B, Status{Mood: "calm"})
+	ctx := core.NewContext(world, ev)
+
+	for field, value := range map[string]any{"mood": "furious", "angry": true} {
+		act := newAction(t, "set_field", map[string]any{"target": "B", "component": "status", "field": field, "value": value})
+		if err := act.Execute(ctx); err != nil {
+			t.Fatalf("set_field %s failed: %v", field, err)
+		}
+	}
+	if got := *statuses.Get(ev.B); got != (Status{Mood: "furious", Angry: true}) {
+		t.Errorf("Expected {furious true}, got %+v", got)
+	}
+
+	act := newAction(t, "set_field", map[string]any{"target": "B", "component": "status", "field": "mood", "value": 3.0})
+	if err := act.Execute(ctx); err == nil {
+		t.Error("Writing a number into a string field should fail")
+	}
+}
+
+func TestAddToField(t *testing.T) {
+	world, healths, ev := actionWorld()
+	ctx := core.NewContext(world, ev)
+
+	act := newAction(t, "add_to_field", map[string]any{"target": "B", "component": "health", "field": "current", "amount": -3.0})
+	if err := act.Execute(ctx); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if err := act.Execute(ctx); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if hp := healths.Get(ev.B); hp.Current != -1 {
+		t.Errorf("Expected B health -1, got %d", hp.Current)
+	}
+}
+
+func TestFieldActionErrors(t *testing.T) {
+	world, _, ev := actionWorld()
+	world.RegisterComponent("opaque", &opaqueStore{})
+	ctx := core.NewContext(world, ev)
+
+	for _, act := range []behavior.Action{
+		&SetField{Component: "mana", Field: "current", Value: 1},
+		&SetField{Component: "opaque", Field: "current", Value: 1},
+		&SetField{Component: "health", Field: "armor", Value: 1},
+		&AddToField{Component: "health", Field: "armor", Amount: 1},
+		&AddToField{Target: TargetB, Component: "health", Field: "current", Amount: 0.5},
+	} {
+		if err := act.Execute(ctx); err == nil {
+			t.Errorf("%+v should fail", act)
+		}
+	}
+}
+
+// opaqueStore is a registered component without field access.
+type opaqueStore struct{}
+
+func (opaqueStore) Has(entity.Entity) bool { return false }
+func (opaqueStore) Remove(entity.Entity)   {}
+func (opaqueStore) Count() int             { return 0 }
+
+// ============================================
+// Emit Tests
+// ============================================
+
+func TestEmitEventTemplates(t *testing.T) {
+	world, _, ev := actionWorld()
+	var emitted []core.Event
+	ctx := core.NewContext(world, ev)
+	ctx.Emitter = func(ev core.Event) { emitted = append(emitted, ev) }
+
+	act := newAction(t, "emit_event", map[string]any{
+		"type": "damaged",
+		"a":    "B",
+		"b":    "none",
+		"payload": map[string]any{
+			"amount": "{{event.damage}}",
+			"note":   "hp {{ B.health.current }}/{{B.health.max}}",
+			"hits":   []any{"{{A.health.current}}", 1.0},
+			"plain":  true,
+		},
+	})
+	if err := act.Execute(ctx); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(emitted) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(emitted))
+	}
+	got := emitted[0]
+	if got.Type != "damaged" || got.A != ev.B || got.B != entity.Null {
+		t.Errorf("Unexpected event header: %+v", got)
+	}
+	if got.Payload["amount"] != 3.0 {
+		t.Errorf("A single placeholder should keep its type, got %#v", got.Payload["amount"])
+	}
+	if got.Payload["note"] != "hp 5/5" {
+		t.Errorf("Expected \"hp 5/5\", got %#v", got.Payload["note"])
+	}
+	if hits := got.Payload["hits"].([]any); !slices.Equal(hits, []any{10, 1.0}) {
+		t.Errorf("Expected [10 1], got %v", hits)
+	}
+	if got.Payload["plain"] != true {
+		t.Errorf("Non-string values should pass through, got %v", got.Payload["plain"])
+	}
+}
+
+func TestEmitEventErrors(t *testing.T) {
+	world, _, ev := actionWorld()
+
+	act := &EmitEvent{Type: "damaged", A: TargetA}
+	if err := act.Execute(core.NewContext(world, ev)); err == nil {
+		t.Error("Emitting without an emitter should fail")
+	}
+
+	ctx := core.NewContext(world, ev)
+	ctx.Emitter = func(core.Event) { t.Error("Nothing should be emitted when a template fails") }
+	for _, payload := range []map[string]any{
+		{"x": "{{event.missing}}"},
+		{"x": "a {{event.damage"},
+	} {
+		act := &EmitEvent{Type: "damaged", Payload: payload}
+		if err := act.Execute(ctx); err == nil {
+			t.Errorf("Payload %v should fail", payload)
+		}
+	}
+}
+
+func TestEmitEventThroughDispatcher(t *testing.T) {
+	world, _, ev := actionWorld()
+	d := behavior.NewDispatcher(world, []*behavior.Behavior{
+		{
+			ID:      "forward",
+			Trigger: behavior.Trigger{Type: "hit"},
+			Actions: []behavior.Action{&EmitEvent{Type: "damaged", A: TargetB, Payload: map[string]any{"amount": "{{event.damage}}"}}},
+		},
+		{
+			ID:      "apply",
+			Trigger: behavior.Trigger{Type: "damaged", Entities: []string{"enemy"}},
+			Actions: []behavior.Action{&AddTag{Tag: "hurt"}},
+		},
+	})
+
+	d.Emit(ev)
+	d.Update(0)
+	d.Update(0)
+
+	if !world.Tags().HasTag(ev.B, "hurt") {
+		t.Error("The emitted event should reach the second behavior")
+	}
+}
@@ -0,0 +1,12 @@
+package actions
+
+import "github.com/GiannisPettas/ember2D/internal/engine/core"
+
+// ConsumeEvent stops the triggering event from reaching behaviors with a
+// lower priority, e.g. a shield rule absorbing a hit before the damage rule.
+type ConsumeEvent struct{}
+
+func (a *ConsumeEvent) Execute(ctx *core.Context) error {
+	ctx.Consume()
+	return nil
+}
@@ -13,7 +13,7 @@ type DebugLog struct {
 	Message string
 }
 
-func (a *DebugLog) Execute(ctx *core.Context) {
+func (a *DebugLog) Execute(ctx *core.Context) error {
 	// We can access the event that triggered this via ctx.Event
 	// For now, just print the static message.
 	log.Printf("[ACTION] DebugLog: %s (Event: %s)", a.Message, ctx.Event.Type)
@@ -21,4 +21,5 @@ func (a *DebugLog) Execute(ctx *core.Context) {
 	if ctx.Event.Payload != nil {
 		fmt.Printf("\tPayload: %v\n", ctx.Event.Payload)
 	}
+	return nil
 }
@@ -0,0 +1,25 @@
+package actions
+
+import "github.com/GiannisPettas/ember2D/internal/engine/core"
+
+// DestroyEntity destroys an event participant, or every entity carrying Tag
+// when it is set. Destruction takes effect at the next World.Cleanup.
+type DestroyEntity struct {
+	Target Target
+	Tag    string
+}
+
+func (a *DestroyEntity) Execute(ctx *core.Context) error {
+	if a.Tag != "" {
+		for _, e := range ctx.World.Tags().GetEntitiesByTag(a.Tag) {
+			ctx.World.DestroyEntity(e)
+		}
+		return nil
+	}
+	e, err := a.Target.entity(ctx.Event)
+	if err != nil {
+		return err
+	}
+	ctx.World.DestroyEntity(e)
+	return nil
+}
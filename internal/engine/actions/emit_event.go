@@ -0,0 +1,110 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// EmitEvent queues a follow-up event. A and B choose which participants of
+// the triggering event it carries (TargetNone for neither); a participant the
+// triggering event lacks is simply left Null.
+//
+// String payload values are templates: "{{path}}" is replaced by
+// ctx.Resolve(path), so {"damage": "{{event.damage}}"} forwards a number and
+// {"msg": "hit by {{B.name.value}}"} builds a string.
+type EmitEvent struct {
+	Type    core.EventType
+	A, B    Target
+	Payload map[string]any
+}
+
+func (a *EmitEvent) Execute(ctx *core.Context) error {
+	payload, err := expandTemplate(ctx, a.Payload)
+	if err != nil {
+		return fmt.Errorf("emit %q: %w", a.Type, err)
+	}
+	ev := core.Event{
+		Type:    a.Type,
+		A:       participant(ctx.Event, a.A),
+		B:       participant(ctx.Event, a.B),
+		Payload: payload.(map[string]any),
+	}
+	return ctx.Emit(ev)
+}
+
+func participant(ev core.Event, t Target) entity.Entity {
+	switch t {
+	case TargetA:
+		return ev.A
+	case TargetB:
+		return ev.B
+	}
+	return entity.Null
+}
+
+// expandTemplate returns a copy of v with every template string in it,
+// including inside nested objects and lists, resolved against ctx.
+func expandTemplate(ctx *core.Context, v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return expandString(ctx, v)
+	case map[string]any:
+		if v == nil {
+			return v, nil
+		}
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			expanded, err := expandTemplate(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := expandTemplate(ctx, item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// expandString resolves the {{path}} placeholders in s. A string that is a
+// single placeholder keeps the resolved value's type.
+func expandString(ctx *core.Context, s string) (any, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	if strings.HasPrefix(s, "{{") && strings.Index(s, "}}") == len(s)-2 {
+		return ctx.Resolve(strings.TrimSpace(s[2 : len(s)-2]))
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		end := strings.Index(s[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated {{ in %q", s)
+		}
+		v, err := ctx.Resolve(strings.TrimSpace(s[start+2 : start+end]))
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(s[:start])
+		fmt.Fprint(&b, v)
+		s = s[start+end+2:]
+	}
+}
@@ -0,0 +1,150 @@
+package actions
+
+import (
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/registry"
+)
+
+// Register adds the built-in actions to r.
+func Register(r *registry.Registry) {
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "debug_log",
+		Description: "Prints a message to the console when the behavior fires.",
+		Params: []registry.ParamSpec{
+			{Name: "message", Type: registry.String, Description: "Text to print."},
+		},
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &DebugLog{Message: p.String("message")}, nil
+		},
+	})
+
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "consume_event",
+		Description: "Stops the event from reaching lower-priority behaviors.",
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &ConsumeEvent{}, nil
+		},
+	})
+
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "spawn_entity",
+		Description: "Creates an entity from a prefab.",
+		Params: []registry.ParamSpec{
+			{Name: "prefab", Type: registry.String, Required: true, Description: "Prefab name, as defined in the scene."},
+			{Name: "tags", Type: registry.String, Description: "Extra comma-separated tags."},
+		},
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &SpawnEntity{Prefab: p.String("prefab"), Tags: splitTags(p.String("tags"))}, nil
+		},
+	})
+
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "destroy_entity",
+		Description: "Destroys an event participant, or every entity with a tag.",
+		Params: []registry.ParamSpec{
+			targetParam(),
+			{Name: "tag", Type: registry.String, Description: "Destroy every entity with this tag instead of the target."},
+		},
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &DestroyEntity{Target: Target(p.String("target")), Tag: p.String("tag")}, nil
+		},
+	})
+
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "add_tag",
+		Description: "Adds a tag to an event participant.",
+		Params:      []registry.ParamSpec{targetParam(), tagParam()},
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &AddTag{Target: Target(p.String("target")), Tag: p.String("tag")}, nil
+		},
+	})
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "remove_tag",
+		Description: "Removes a tag from an event participant.",
+		Params:      []registry.ParamSpec{targetParam(), tagParam()},
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &RemoveTag{Target: Target(p.String("target")), Tag: p.String("tag")}, nil
+		},
+	})
+
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "set_field",
+		Description: "Sets a number, string or bool component field of an event participant.",
+		Params: append(fieldParams(),
+			registry.ParamSpec{Name: "value", Type: registry.Scalar, Required: true},
+		),
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &SetField{
+				Target:    Target(p.String("target")),
+				Component: p.String("component"),
+				Field:     p.String("field"),
+				Value:     p.Scalar("value"),
+			}, nil
+		},
+	})
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "add_to_field",
+		Description: "Adds to a numeric component field of an event participant.",
+		Params: append(fieldParams(),
+			registry.ParamSpec{Name: "amount", Type: registry.Number, Required: true, Description: "Negative to subtract."},
+		),
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &AddToField{
+				Target:    Target(p.String("target")),
+				Component: p.String("component"),
+				Field:     p.String("field"),
+				Amount:    p.Float("amount"),
+			}, nil
+		},
+	})
+
+	participants := []string{string(TargetA), string(TargetB), string(TargetNone)}
+	r.RegisterAction(registry.ActionSpec{
+		Name:        "emit_event",
+		Description: "Queues a follow-up event.",
+		Params: []registry.ParamSpec{
+			{Name: "type", Type: registry.String, Required: true},
+			{Name: "a", Type: registry.String, Default: "A", Options: participants, Description: "Participant carried as A."},
+			{Name: "b", Type: registry.String, Default: "B", Options: participants, Description: "Participant carried as B."},
+			{Name: "payload", Type: registry.Object, Description: `String values may contain {{path}} templates, e.g. "{{event.damage}}".`},
+		},
+		New: func(p registry.Params) (behavior.Action, error) {
+			return &EmitEvent{
+				Type:    core.EventType(p.String("type")),
+				A:       Target(p.String("a")),
+				B:       Target(p.String("b")),
+				Payload: p.Object("payload"),
+			}, nil
+		},
+	})
+}
+
+func targetParam() registry.ParamSpec {
+	return registry.ParamSpec{Name: "target", Type: registry.String, Default: "A", Options: targets, Description: "Event participant to act on."}
+}
+
+func tagParam() registry.ParamSpec {
+	return registry.ParamSpec{Name: "tag", Type: registry.String, Required: true}
+}
+
+func fieldParams() []registry.ParamSpec {
+	return []registry.ParamSpec{
+		targetParam(),
+		{Name: "component", Type: registry.String, Required: true, Description: "Registered component name, e.g. \"health\"."},
+		{Name: "field", Type: registry.String, Required: true, Description: "Field path, e.g. \"current\"."},
+	}
+}
+
+// splitTags splits a comma-separated tag list, dropping empty entries.
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
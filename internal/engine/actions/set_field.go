@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// SetField writes Value into a field of an event participant's component,
+// e.g. Component "health", Field "current". Value is a number, string or
+// bool; numbers are converted to the field's type by the component store.
+type SetField struct {
+	Target    Target
+	Component string
+	Field     string
+	Value     any
+}
+
+func (a *SetField) Execute(ctx *core.Context) error {
+	e, store, err := fieldTarget(ctx, a.Target, a.Component)
+	if err != nil {
+		return err
+	}
+	if err := store.SetField(e, a.Field, a.Value); err != nil {
+		return fmt.Errorf("%s.%s: %w", a.Component, a.Field, err)
+	}
+	return nil
+}
+
+// AddToField adds Amount to a numeric field of an event participant's
+// component. A negative Amount subtracts.
+type AddToField struct {
+	Target    Target
+	Component string
+	Field     string
+	Amount    float64
+}
+
+func (a *AddToField) Execute(ctx *core.Context) error {
+	e, store, err := fieldTarget(ctx, a.Target, a.Component)
+	if err != nil {
+		return err
+	}
+	reader, ok := store.(core.FieldReader)
+	if !ok {
+		return fmt.Errorf("component %q cannot be read by field", a.Component)
+	}
+	v, err := reader.GetField(e, a.Field)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", a.Component, a.Field, err)
+	}
+	n, ok := core.ToFloat(v)
+	if !ok {
+		return fmt.Errorf("%s.%s: %T is not a number", a.Component, a.Field, v)
+	}
+	if err := store.SetField(e, a.Field, n+a.Amount); err != nil {
+		return fmt.Errorf("%s.%s: %w", a.Component, a.Field, err)
+	}
+	return nil
+}
+
+// fieldTarget resolves the participant and the writable store named by a
+// field action.
+func fieldTarget(ctx *core.Context, target Target, component string) (entity.Entity, core.FieldWriter, error) {
+	e, err := target.entity(ctx.Event)
+	if err != nil {
+		return entity.Null, nil, err
+	}
+	store, ok := ctx.Component(component).(core.FieldWriter)
+	if !ok {
+		return entity.Null, nil, fmt.Errorf("unknown or unwritable component %q", component)
+	}
+	return e, store, nil
+}
@@ -0,0 +1,15 @@
+package actions
+
+import "github.com/GiannisPettas/ember2D/internal/engine/core"
+
+// SpawnEntity creates an entity from a prefab defined in the World, adding
+// Tags on top of the prefab's own.
+type SpawnEntity struct {
+	Prefab string
+	Tags   []string
+}
+
+func (a *SpawnEntity) Execute(ctx *core.Context) error {
+	_, err := ctx.World.Spawn(a.Prefab, a.Tags...)
+	return err
+}
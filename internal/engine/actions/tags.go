@@ -0,0 +1,33 @@
+package actions
+
+import "github.com/GiannisPettas/ember2D/internal/engine/core"
+
+// AddTag tags an event participant.
+type AddTag struct {
+	Target Target
+	Tag    string
+}
+
+func (a *AddTag) Execute(ctx *core.Context) error {
+	e, err := a.Target.entity(ctx.Event)
+	if err != nil {
+		return err
+	}
+	ctx.World.Tags().AddTag(e, a.Tag)
+	return nil
+}
+
+// RemoveTag removes a tag from an event participant.
+type RemoveTag struct {
+	Target Target
+	Tag    string
+}
+
+func (a *RemoveTag) Execute(ctx *core.Context) error {
+	e, err := a.Target.entity(ctx.Event)
+	if err != nil {
+		return err
+	}
+	ctx.World.Tags().RemoveTag(e, a.Tag)
+	return nil
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Target picks an event participant for an action to work on.
+type Target string
+
+const (
+	TargetA    Target = "A"
+	TargetB    Target = "B"
+	TargetNone Target = "none" // only meaningful for EmitEvent
+)
+
+// targets are the registry options for a "target" parameter.
+var targets = []string{string(TargetA), string(TargetB)}
+
+// entity returns the participant t names in ev. Targeting a participant the
+// event does not have is an error.
+func (t Target) entity(ev core.Event) (entity.Entity, error) {
+	var e entity.Entity
+	switch t {
+	case TargetA, "":
+		e = ev.A
+	case TargetB:
+		e = ev.B
+	case TargetNone:
+		return entity.Null, nil
+	default:
+		return entity.Null, fmt.Errorf("unknown target %q", t)
+	}
+	if e == entity.Null {
+		return entity.Null, fmt.Errorf("event %q has no participant %s", ev.Type, t)
+	}
+	return e, nil
+}
@@ -3,11 +3,20 @@ package behavior
 import "github.com/GiannisPettas/ember2D/internal/engine/core"
 
 // Behavior is a compiled rule: trigger + conditions + actions.
+// Behaviors with a higher Priority see an event first and may consume it
+// (core.Context.Consume); equal priorities keep their slice order.
+// Disabled behaviors and behaviors in a disabled Group are skipped, see
+// Dispatcher.SetEnabled and Dispatcher.SetGroupEnabled.
 type Behavior struct {
 	ID         string
+	Priority   int
+	Group      string // optional, e.g. "boss_phase_2"
+	Disabled   bool
 	Trigger    Trigger
 	Conditions []Condition
 	Actions    []Action
+
+	removed bool // set by Dispatcher.RemoveBehavior, for events mid-flight
 }
 
 // Condition is a logic block that can block a behavior from running.
@@ -16,6 +25,8 @@ type Condition interface {
 }
 
 // Action is a logic block that mutates the world / entities.
+// Returning an error (e.g. a missing component) stops the behavior's
+// remaining actions; the Dispatcher records and reports it.
 type Action interface {
-	Execute(ctx *core.Context)
+	Execute(ctx *core.Context) error
 }
@@ -0,0 +1,91 @@
+package behavior
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Cascade budgets used when the Dispatcher fields are 0.
+const (
+	DefaultMaxCascadeEvents = 10000
+	DefaultMaxCascadeDepth  = 64
+)
+
+// Errors reported through Dispatcher.OnError in cascade mode. The events
+// involved are not dropped: they are processed on the next Update.
+var (
+	ErrCascadeLimit = errors.New("cascade event budget exceeded")
+	ErrCascadeDepth = errors.New("cascade depth exceeded")
+	ErrCascadeCycle = errors.New("cascade cycle detected")
+)
+
+// queuedEvent is an event waiting in the dispatcher queue together with the
+// same-frame chain of events that caused it.
+type queuedEvent struct {
+	ev    core.Event
+	chain []eventKey
+}
+
+// eventKey identifies an event for cycle detection. Payloads are ignored, so
+// damage → death → damage on the same entities is a cycle even if the
+// amounts differ.
+type eventKey struct {
+	typ  core.EventType
+	a, b entity.Entity
+}
+
+func keyOf(ev core.Event) eventKey {
+	return eventKey{typ: ev.Type, a: ev.A, b: ev.B}
+}
+
+// cascade processes queue and every event emitted meanwhile, breadth first.
+// Events over the depth budget or repeating an ancestor are deferred to the
+// next frame; once the event budget is spent everything left is deferred.
+func (d *Dispatcher) cascade(queue []queuedEvent) {
+	maxEvents := d.MaxCascadeEvents
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxCascadeEvents
+	}
+	maxDepth := d.MaxCascadeDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxCascadeDepth
+	}
+
+	var deferred []queuedEvent
+	processed := 0
+	for len(queue) > 0 {
+		for i, q := range queue {
+			if processed >= maxEvents {
+				d.reportError(fmt.Errorf("%w (%d events this frame)", ErrCascadeLimit, maxEvents))
+				deferred = append(deferred, queue[i:]...)
+				deferred = append(deferred, d.eventQueue...)
+				d.eventQueue = nil
+				break
+			}
+			switch {
+			case q.ev.Depth > maxDepth:
+				d.reportError(fmt.Errorf("event %q: %w (%d)", q.ev.Type, ErrCascadeDepth, maxDepth))
+				deferred = append(deferred, q)
+			case slices.Contains(q.chain, keyOf(q.ev)):
+				d.reportError(fmt.Errorf("event %q (A=%d, B=%d): %w", q.ev.Type, q.ev.A, q.ev.B, ErrCascadeCycle))
+				deferred = append(deferred, q)
+			default:
+				processed++
+				d.processEvent(q)
+			}
+		}
+		queue = d.eventQueue
+		d.eventQueue = nil
+	}
+
+	// Deferred events start a fresh chain next frame.
+	for i := range deferred {
+		deferred[i].ev.Depth = 0
+		deferred[i].chain = nil
+	}
+	d.eventQueue = append(deferred, d.eventQueue...)
+}
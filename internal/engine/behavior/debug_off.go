@@ -0,0 +1,9 @@
+//go:build !ember2d_debug
+
+package behavior
+
+import "github.com/GiannisPettas/ember2D/internal/engine/core"
+
+// validatePayload is a no-op outside ember2d_debug builds, so release builds
+// pay nothing for payload schemas.
+func (d *Dispatcher) validatePayload(ev core.Event) {}
@@ -0,0 +1,6 @@
+//go:build !ember2d_debug
+
+package behavior
+
+// debugBuild tells tests whether validatePayload is active.
+const debugBuild = false
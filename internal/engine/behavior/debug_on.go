@@ -0,0 +1,21 @@
+//go:build ember2d_debug
+
+package behavior
+
+import (
+	"fmt"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+)
+
+// validatePayload reports events whose payload does not match the schema
+// registered for their type. The event is still queued.
+func (d *Dispatcher) validatePayload(ev core.Event) {
+	schema, ok := d.payloads[ev.Type]
+	if !ok {
+		return
+	}
+	if err := schema.Validate(ev); err != nil {
+		d.reportError(fmt.Errorf("emit %q: %w", ev.Type, err))
+	}
+}
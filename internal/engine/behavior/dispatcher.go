@@ -1,73 +1,353 @@
 package behavior
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"time"
+
 	"github.com/GiannisPettas/ember2D/internal/engine/core"
 	"github.com/GiannisPettas/ember2D/internal/engine/entity"
 )
 
+// DefaultMaxLoopDepth is used when Dispatcher.MaxLoopDepth is 0.
+const DefaultMaxLoopDepth = 1000
+
+// DefaultSeed seeds the random source of a new Dispatcher.
+const DefaultSeed uint64 = 1
+
+// ErrLoopLimit is reported when a loop behavior exceeds MaxLoopDepth
+// iterations in one frame.
+var ErrLoopLimit = errors.New("loop limit exceeded")
+
 // Dispatcher receives events and routes them to matching behaviors.
 type Dispatcher struct {
 	World      *entity.World
 	Behaviors  []*Behavior
-	eventQueue []core.Event
+	eventQueue []queuedEvent
+
+	// Cascade makes Update also process events emitted while it runs, so a
+	// chain like explosion → damage → death resolves in one frame. Without
+	// it those events wait for the next Update.
+	Cascade bool
+
+	// MaxCascadeEvents caps how many events one Update processes in cascade
+	// mode; the rest wait for the next frame. 0 means DefaultMaxCascadeEvents.
+	MaxCascadeEvents int
+
+	// MaxCascadeDepth caps how long a same-frame chain may grow (see
+	// core.Event.Depth). 0 means DefaultMaxCascadeDepth.
+	MaxCascadeDepth int
+
+	// MaxLoopDepth caps how many iterations a loop behavior may run per
+	// frame. 0 means DefaultMaxLoopDepth.
+	MaxLoopDepth int
+
+	// OnError receives errors raised while processing events: a
+	// *BehaviorError for failing actions and loop limits, or dispatcher
+	// errors such as ErrCascadeCycle. Nil logs them.
+	OnError func(err error)
+
+	loopDepth map[*Behavior]int // iterations run this frame
+	current   *queuedEvent      // event being processed, parent of emitted events
+	order     []*Behavior       // Behaviors by descending priority, refreshed each Update
+
+	disabledGroups map[string]bool
+	payloads       map[core.EventType]core.PayloadSchema
+	failures       map[string][]*BehaviorError
+
+	timers  map[*Behavior]*timer
+	paused  bool
+	started bool
+	frame   int
+
+	rng     *rand.Rand
+	delta   time.Duration                // dt of the current Update
+	scratch map[*Behavior]map[string]any // per-behavior core.Context.Scratch
 }
 
 func NewDispatcher(world *entity.World, behaviors []*Behavior) *Dispatcher {
-	return &Dispatcher{
+	d := &Dispatcher{
 		World:     world,
 		Behaviors: behaviors,
+		payloads:  make(map[core.EventType]core.PayloadSchema),
 	}
+	d.Seed(DefaultSeed)
+	d.RegisterPayload(core.EventTick, core.PayloadSchema{
+		{Key: "dt", Type: core.PayloadNumber, Required: true},
+		{Key: "frame", Type: core.PayloadInt, Required: true},
+	})
+	d.RegisterPayload(core.EventTimer, core.PayloadSchema{
+		{Key: "behavior", Type: core.PayloadString, Required: true},
+		{Key: "count", Type: core.PayloadInt, Required: true},
+	})
+	for _, t := range []core.EventType{core.EventTagAdded, core.EventTagRemoved} {
+		d.RegisterPayload(t, core.PayloadSchema{{Key: "tag", Type: core.PayloadString, Required: true}})
+	}
+	return d
+}
+
+// Seed resets the random source handed to behaviors as core.Context.Rand.
+// Two dispatchers with the same seed, behaviors and events make the same
+// random choices.
+func (d *Dispatcher) Seed(seed uint64) {
+	d.rng = rand.New(rand.NewPCG(seed, 0))
+}
+
+// RegisterPayload declares the payload schema of an event type, replacing
+// any previous one. Builds with the ember2d_debug tag check every emitted
+// event against it and report mismatches through OnError; other builds skip
+// the check.
+func (d *Dispatcher) RegisterPayload(t core.EventType, schema core.PayloadSchema) {
+	d.payloads[t] = schema
+}
+
+// PayloadSchema returns the schema registered for t.
+func (d *Dispatcher) PayloadSchema(t core.EventType) (core.PayloadSchema, bool) {
+	schema, ok := d.payloads[t]
+	return schema, ok
 }
 
-// Emit adds an event to the queue.
+// Emit adds an event to the queue. In cascade mode an event emitted while
+// another is being processed becomes its child: its Depth is one more than
+// the parent's.
 func (d *Dispatcher) Emit(ev core.Event) {
-	d.eventQueue = append(d.eventQueue, ev)
+	d.validatePayload(ev)
+	q := queuedEvent{ev: ev}
+	if cur := d.current; cur != nil && d.Cascade {
+		q.ev.Depth = cur.ev.Depth + 1
+		q.chain = append(slices.Clip(cur.chain), keyOf(cur.ev))
+	}
+	d.eventQueue = append(d.eventQueue, q)
 }
 
-// Update processes all queued events.
-func (d *Dispatcher) Update() {
-	if len(d.eventQueue) == 0 {
+// Start emits the start event. Call it once the scene is loaded; later calls
+// do nothing.
+func (d *Dispatcher) Start() {
+	if d.started {
 		return
 	}
+	d.started = true
+	d.Emit(core.Event{Type: core.EventStart})
+}
+
+// EmitEntityEvents makes the dispatcher emit entity_created /
+// entity_destroyed events for the world's entities, with the entity in A.
+func (d *Dispatcher) EmitEntityEvents() {
+	d.World.OnEntityCreated(func(e entity.Entity) {
+		d.Emit(entityEvent(core.EventEntityCreated, e))
+	})
+	d.World.OnEntityDestroyed(func(e entity.Entity) {
+		d.Emit(entityEvent(core.EventEntityDestroyed, e))
+	})
+}
+
+func entityEvent(t core.EventType, e entity.Entity) core.Event {
+	return core.Event{
+		Type: t,
+		A:    e,
+	}
+}
+
+// EmitTagEvents makes the dispatcher emit tag_added / tag_removed events
+// whenever a tag changes in the world's TagManager, so behaviors can react to
+// e.g. an entity becoming "stunned" without polling. A holds the entity and
+// Payload["tag"] the normalized tag.
+func (d *Dispatcher) EmitTagEvents() {
+	tags := d.World.Tags()
+	tags.OnTagAdded(func(e entity.Entity, tag string) {
+		d.Emit(tagEvent(core.EventTagAdded, e, tag))
+	})
+	tags.OnTagRemoved(func(e entity.Entity, tag string) {
+		d.Emit(tagEvent(core.EventTagRemoved, e, tag))
+	})
+}
+
+func tagEvent(t core.EventType, e entity.Entity, tag string) core.Event {
+	return core.Event{
+		Type:    t,
+		A:       e,
+		Payload: map[string]any{"tag": tag},
+	}
+}
+
+// Update advances timers by dt (the frame delta), emits a tick event and
+// processes all queued events, plus the events they cause when Cascade is
+// set. It does nothing while the dispatcher is paused.
+func (d *Dispatcher) Update(dt time.Duration) {
+	if d.paused {
+		return
+	}
+	d.frame++
+	d.delta = dt
+	clear(d.loopDepth)
+	d.sortBehaviors()
+	d.advanceTimers(dt)
+	d.Emit(core.Event{
+		Type:    core.EventTick,
+		Payload: map[string]any{"dt": dt.Seconds(), "frame": d.frame},
+	})
 
 	queue := d.eventQueue
 	d.eventQueue = nil
 
-	for _, ev := range queue {
-		d.processEvent(ev)
+	if d.Cascade {
+		d.cascade(queue)
+		return
+	}
+	for _, q := range queue {
+		d.processEvent(q)
 	}
 }
 
-func (d *Dispatcher) processEvent(ev core.Event) {
-	for _, b := range d.Behaviors {
-		// 1. Trigger match
-		if !b.Trigger.Matches(ev) {
-			continue
-		}
+// sortBehaviors refreshes the processing order from Behaviors. It builds a
+// new slice so an event being processed keeps iterating the old order.
+func (d *Dispatcher) sortBehaviors() {
+	order := slices.Clone(d.Behaviors)
+	slices.SortStableFunc(order, func(a, b *Behavior) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+	d.order = order
+}
 
-		// 2. Build context
-		ctx := core.NewContext(d.World, ev)
+func (d *Dispatcher) processEvent(q queuedEvent) {
+	d.current = &q
+	defer func() { d.current = nil }()
 
-		// 3. Conditions
-		ok := true
-		for _, cond := range b.Conditions {
-			if !cond.Evaluate(ctx) {
-				ok = false
-				break
-			}
+	ev := q.ev
+	for _, b := range d.order {
+		// 1. Trigger match (timers are scheduled, not matched)
+		if !d.active(b) || b.Trigger.Type == TriggerTimer {
+			continue
 		}
+		bev, ok := b.Trigger.Match(ev, d.World)
 		if !ok {
 			continue
 		}
+		if b.Trigger.Type == TriggerLoop {
+			if d.runLoop(b, bev) {
+				return
+			}
+			continue
+		}
+		if ctx := d.runBehavior(b, bev); ctx != nil && ctx.Consumed() {
+			return
+		}
+	}
+}
 
-		// 4. Actions
-		for _, act := range b.Actions {
-			act.Execute(ctx)
+// runBehavior runs one behavior for ev if its conditions pass. It returns
+// the context the actions ran with, or nil if a condition failed or an action
+// failed. A failing or panicking action stops the behavior's remaining
+// actions and is reported as a *BehaviorError.
+func (d *Dispatcher) runBehavior(b *Behavior, ev core.Event) *core.Context {
+	// 2. Build context
+	ctx := d.newContext(b, ev)
+
+	// 3. Conditions
+	for _, cond := range b.Conditions {
+		if !cond.Evaluate(ctx) {
+			return nil
 		}
+	}
 
-		// 5. LoopBack support (simple version)
-		if b.Trigger.Type == "loop" {
-			d.Emit(ev)
+	// 4. Actions
+	for i, act := range b.Actions {
+		if err := d.execute(b, i, act, ctx); err != nil {
+			d.fail(err)
+			return nil
 		}
 	}
+	return ctx
+}
+
+// newContext builds the context b runs with, bound to this dispatcher.
+func (d *Dispatcher) newContext(b *Behavior, ev core.Event) *core.Context {
+	scratch := d.scratch[b]
+	if scratch == nil {
+		if d.scratch == nil {
+			d.scratch = make(map[*Behavior]map[string]any)
+		}
+		scratch = make(map[string]any)
+		d.scratch[b] = scratch
+	}
+
+	ctx := core.NewContext(d.World, ev)
+	ctx.Emitter = d.Emit
+	ctx.Rand = d.rng
+	ctx.Delta = d.delta
+	ctx.Scratch = scratch
+	return ctx
+}
+
+// execute runs one action, turning a panic into a *BehaviorError.
+func (d *Dispatcher) execute(b *Behavior, index int, act Action, ctx *core.Context) (err *BehaviorError) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &BehaviorError{
+				BehaviorID: b.ID,
+				Event:      ctx.Event,
+				Action:     index,
+				Err:        fmt.Errorf("panic: %v", r),
+				Panic:      r,
+			}
+		}
+	}()
+	if e := act.Execute(ctx); e != nil {
+		return &BehaviorError{BehaviorID: b.ID, Event: ctx.Event, Action: index, Err: e}
+	}
+	return nil
+}
+
+// runLoop iterates a loop behavior for one event. It stops after Count
+// iterations, once Until holds, when the conditions fail, when an action
+// consumes the event, or with ErrLoopLimit when the behavior exceeds
+// MaxLoopDepth iterations this frame. It reports whether the event was
+// consumed.
+func (d *Dispatcher) runLoop(b *Behavior, ev core.Event) bool {
+	limit := d.MaxLoopDepth
+	if limit <= 0 {
+		limit = DefaultMaxLoopDepth
+	}
+	if d.loopDepth == nil {
+		d.loopDepth = make(map[*Behavior]int)
+	}
+
+	for ev.Loop = 0; b.Trigger.Count <= 0 || ev.Loop < b.Trigger.Count; ev.Loop++ {
+		if !d.active(b) {
+			return false // disabled or removed by one of its own actions
+		}
+		if b.Trigger.Until != nil && b.Trigger.Until.Evaluate(d.newContext(b, ev)) {
+			return false
+		}
+		if d.loopDepth[b] >= limit {
+			d.fail(&BehaviorError{
+				BehaviorID: b.ID,
+				Event:      ev,
+				Action:     -1,
+				Err:        fmt.Errorf("%w (%d iterations this frame)", ErrLoopLimit, limit),
+			})
+			return false
+		}
+		d.loopDepth[b]++
+		ctx := d.runBehavior(b, ev)
+		if ctx == nil {
+			return false
+		}
+		if ctx.Consumed() {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dispatcher) reportError(err error) {
+	if d.OnError != nil {
+		d.OnError(err)
+		return
+	}
+	log.Printf("ember2d: %v", err)
 }
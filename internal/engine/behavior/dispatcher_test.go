@@ -0,0 +1,999 @@
+package behavior
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// recordAction remembers every event it was executed for.
+type recordAction struct {
+	events []core.Event
+}
+
+func (a *recordAction) Execute(ctx *core.Context) error {
+	a.events = append(a.events, ctx.Event)
+	return nil
+}
+
+// funcAction adapts a function to Action.
+type funcAction func(ctx *core.Context)
+
+func (f funcAction) Execute(ctx *core.Context) error {
+	f(ctx)
+	return nil
+}
+
+// ============================================
+// Tag Event Tests
+// ============================================
+
+func TestEmitTagEvents(t *testing.T) {
+	world := entity.NewWorld()
+	added := &recordAction{}
+	removed := &recordAction{}
+	d := NewDispatcher(world, []*Behavior{
+		{ID: "on_added", Trigger: Trigger{Type: "tag_added"}, Actions: []Action{added}},
+		{ID: "on_removed", Trigger: Trigger{Type: "tag_removed"}, Actions: []Action{removed}},
+	})
+	d.EmitTagEvents()
+
+	e := world.CreateEntity()
+	world.Tags().AddTag(e, "Stunned")
+	world.Tags().AddTag(e, "stunned") // duplicate, no event
+	world.Tags().RemoveTag(e, "stunned")
+	d.Update(0)
+
+	if len(added.events) != 1 {
+		t.Fatalf("Expected 1 tag_added event, got %d", len(added.events))
+	}
+	ev := added.events[0]
+	if ev.Payload["tag"] != "stunned" || ev.A != e {
+		t.Errorf("Unexpected payload %v", ev.Payload)
+	}
+	if len(removed.events) != 1 {
+		t.Errorf("Expected 1 tag_removed event, got %d", len(removed.events))
+	}
+}
+
+func TestEmitTagEventsOnCleanup(t *testing.T) {
+	world := entity.NewWorld()
+	removed := &recordAction{}
+	d := NewDispatcher(world, []*Behavior{
+		{ID: "on_removed", Trigger: Trigger{Type: "tag_removed"}, Actions: []Action{removed}},
+	})
+	d.EmitTagEvents()
+
+	e := world.CreateEntity("enemy", "boss")
+	world.DestroyEntity(e)
+	world.Cleanup()
+	d.Update(0)
+
+	if len(removed.events) != 2 {
+		t.Fatalf("Expected 2 tag_removed events, got %d", len(removed.events))
+	}
+	if removed.events[0].Payload["tag"] != "boss" || removed.events[1].Payload["tag"] != "enemy" {
+		t.Error("Cleanup should emit tag_removed in sorted tag order")
+	}
+}
+
+// ============================================
+// Timer Tests
+// ============================================
+
+// runFrames calls Update n times with the given frame delta.
+func runFrames(d *Dispatcher, n int, dt time.Duration) {
+	for i := 0; i < n; i++ {
+		d.Update(dt)
+	}
+}
+
+func TestTimerInterval(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "spawner", Trigger: Trigger{Type: "timer", Interval: 100}, Actions: []Action{rec}},
+	})
+
+	runFrames(d, 9, 10*time.Millisecond)
+	if len(rec.events) != 0 {
+		t.Fatalf("Timer fired early: %d times", len(rec.events))
+	}
+	d.Update(10 * time.Millisecond)
+	if len(rec.events) != 1 {
+		t.Fatalf("Expected 1 fire after 100ms, got %d", len(rec.events))
+	}
+	runFrames(d, 20, 10*time.Millisecond)
+	if len(rec.events) != 3 {
+		t.Errorf("Expected 3 fires after 300ms, got %d", len(rec.events))
+	}
+	if rec.events[2].Payload["count"] != 3 || rec.events[2].Payload["behavior"] != "spawner" {
+		t.Errorf("Unexpected payload %v", rec.events[2].Payload)
+	}
+}
+
+func TestTimerCatchesUpOnLongFrames(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "t", Trigger: Trigger{Type: "timer", Interval: 100}, Actions: []Action{rec}},
+	})
+
+	d.Update(350 * time.Millisecond)
+	if len(rec.events) != 3 {
+		t.Errorf("Expected 3 fires in a 350ms frame, got %d", len(rec.events))
+	}
+	d.Update(50 * time.Millisecond)
+	if len(rec.events) != 4 {
+		t.Errorf("Remainder should carry over, got %d fires", len(rec.events))
+	}
+}
+
+func TestTimerOneShotDelay(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "once", Trigger: Trigger{Type: "timer", Delay: 500}, Actions: []Action{rec}},
+	})
+
+	runFrames(d, 4, 100*time.Millisecond)
+	if len(rec.events) != 0 {
+		t.Fatal("One-shot fired before its delay")
+	}
+	runFrames(d, 20, 100*time.Millisecond)
+	if len(rec.events) != 1 {
+		t.Errorf("Expected exactly 1 fire, got %d", len(rec.events))
+	}
+}
+
+func TestTimerDelayThenRepeat(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "burst", Trigger: Trigger{Type: "timer", Delay: 1000, Interval: 100, Repeat: 3}, Actions: []Action{rec}},
+	})
+
+	d.Update(999 * time.Millisecond)
+	if len(rec.events) != 0 {
+		t.Fatal("Fired before the delay")
+	}
+	d.Update(time.Millisecond)
+	if len(rec.events) != 1 {
+		t.Fatalf("Expected first fire at the delay, got %d", len(rec.events))
+	}
+	d.Update(time.Second)
+	if len(rec.events) != 3 {
+		t.Errorf("Repeat should cap fires at 3, got %d", len(rec.events))
+	}
+}
+
+func TestTimerTicks(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "t", Trigger: Trigger{Type: "timer", Interval: 3, Ticks: true}, Actions: []Action{rec}},
+	})
+
+	runFrames(d, 9, time.Hour) // dt is ignored in tick mode
+	if len(rec.events) != 3 {
+		t.Errorf("Expected 3 fires in 9 ticks, got %d", len(rec.events))
+	}
+}
+
+func TestTimerIgnoresEmittedTimerEvents(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "t", Trigger: Trigger{Type: "timer", Interval: 1000}, Actions: []Action{rec}},
+	})
+
+	d.Emit(core.Event{Type: core.EventTimer})
+	d.Update(0)
+	if len(rec.events) != 0 {
+		t.Error("Timer behaviors should only run on schedule")
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	timed := &recordAction{}
+	custom := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "t", Trigger: Trigger{Type: "timer", Interval: 100}, Actions: []Action{timed}},
+		{ID: "c", Trigger: Trigger{Type: "custom"}, Actions: []Action{custom}},
+	})
+
+	d.Pause()
+	if !d.Paused() {
+		t.Fatal("Paused() should report true")
+	}
+	d.Emit(core.Event{Type: "custom"})
+	runFrames(d, 10, 100*time.Millisecond)
+	if len(timed.events) != 0 || len(custom.events) != 0 {
+		t.Fatal("Nothing should run while paused")
+	}
+
+	d.Resume()
+	d.Update(100 * time.Millisecond)
+	if len(timed.events) != 1 {
+		t.Errorf("Timer should resume where it stopped, got %d fires", len(timed.events))
+	}
+	if len(custom.events) != 1 {
+		t.Errorf("Queued event should run after Resume, got %d", len(custom.events))
+	}
+}
+
+// ============================================
+// Lifecycle Event Tests
+// ============================================
+
+func TestStartEmittedOnce(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "init", Trigger: Trigger{Type: "start"}, Actions: []Action{rec}},
+	})
+
+	d.Start()
+	d.Start()
+	runFrames(d, 3, 0)
+	if len(rec.events) != 1 {
+		t.Errorf("Expected 1 start event, got %d", len(rec.events))
+	}
+}
+
+func TestTickEveryUpdate(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "each_frame", Trigger: Trigger{Type: "tick"}, Actions: []Action{rec}},
+	})
+
+	runFrames(d, 3, 16*time.Millisecond)
+	if len(rec.events) != 3 {
+		t.Fatalf("Expected 3 ticks, got %d", len(rec.events))
+	}
+	last := rec.events[2].Payload
+	if last["dt"] != 0.016 || last["frame"] != 3 {
+		t.Errorf("Unexpected tick payload %v", last)
+	}
+
+	d.Pause()
+	d.Update(16 * time.Millisecond)
+	if len(rec.events) != 3 {
+		t.Error("No tick should be emitted while paused")
+	}
+}
+
+func TestEmitEntityEvents(t *testing.T) {
+	world := entity.NewWorld()
+	created := &recordAction{}
+	destroyed := &recordAction{}
+	d := NewDispatcher(world, []*Behavior{
+		{ID: "spawned", Trigger: Trigger{Type: "entity_created"}, Actions: []Action{created}},
+		{ID: "died", Trigger: Trigger{Type: "entity_destroyed"}, Actions: []Action{destroyed}},
+	})
+	d.EmitEntityEvents()
+
+	e := world.CreateEntity("enemy")
+	world.DestroyEntity(e)
+	d.Update(0)
+
+	if len(created.events) != 1 || created.events[0].A != e {
+		t.Errorf("Expected one entity_created for %v, got %v", e, created.events)
+	}
+	if len(destroyed.events) != 1 || destroyed.events[0].A != e {
+		t.Errorf("Expected one entity_destroyed for %v, got %v", e, destroyed.events)
+	}
+}
+
+// ============================================
+// Loop Tests
+// ============================================
+
+// funcCondition adapts a function to Condition.
+type funcCondition func(ctx *core.Context) bool
+
+func (f funcCondition) Evaluate(ctx *core.Context) bool {
+	return f(ctx)
+}
+
+func TestLoopCount(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "repeat", Trigger: Trigger{Type: "loop", Count: 3}, Actions: []Action{rec}},
+	})
+
+	d.Emit(core.Event{Type: "loop"})
+	d.Update(0)
+	if len(rec.events) != 3 {
+		t.Fatalf("Expected 3 iterations, got %d", len(rec.events))
+	}
+	for i, ev := range rec.events {
+		if ev.Loop != i {
+			t.Errorf("Iteration %d saw Loop=%d", i, ev.Loop)
+		}
+	}
+
+	runFrames(d, 3, 0)
+	if len(rec.events) != 3 {
+		t.Error("A finished loop should not re-run on later frames")
+	}
+}
+
+func TestLoopCountersPerEvent(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "repeat", Trigger: Trigger{Type: "loop", Count: 2}, Actions: []Action{rec}},
+	})
+
+	d.Emit(core.Event{Type: "loop", A: 1})
+	d.Emit(core.Event{Type: "loop", A: 2})
+	d.Update(0)
+
+	if len(rec.events) != 4 {
+		t.Fatalf("Expected 2 iterations per event, got %d", len(rec.events))
+	}
+	if rec.events[2].A != 2 || rec.events[2].Loop != 0 {
+		t.Errorf("Second event should start its own counter, got %+v", rec.events[2])
+	}
+}
+
+func TestLoopUntil(t *testing.T) {
+	world := entity.NewWorld()
+	rec := &recordAction{}
+	until := funcCondition(func(ctx *core.Context) bool {
+		return ctx.World.EntityCount() >= 5
+	})
+	spawn := funcAction(func(ctx *core.Context) { ctx.World.CreateEntity() })
+	d := NewDispatcher(world, []*Behavior{
+		{ID: "fill", Trigger: Trigger{Type: "loop", Until: until}, Actions: []Action{spawn, rec}},
+	})
+
+	d.Emit(core.Event{Type: "loop"})
+	d.Update(0)
+	if world.EntityCount() != 5 || len(rec.events) != 5 {
+		t.Errorf("Expected 5 iterations, got %d entities and %d runs", world.EntityCount(), len(rec.events))
+	}
+}
+
+func TestLoopStopsWhenConditionsFail(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{
+			ID:         "guarded",
+			Trigger:    Trigger{Type: "loop", Count: 10},
+			Conditions: []Condition{funcCondition(func(ctx *core.Context) bool { return ctx.Event.Loop < 4 })},
+			Actions:    []Action{rec},
+		},
+	})
+
+	d.Emit(core.Event{Type: "loop"})
+	d.Update(0)
+	if len(rec.events) != 4 {
+		t.Errorf("Expected 4 iterations, got %d", len(rec.events))
+	}
+}
+
+func TestLoopLimit(t *testing.T) {
+	rec := &recordAction{}
+	var errs []error
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "forever", Trigger: Trigger{Type: "loop"}, Actions: []Action{rec}},
+	})
+	d.MaxLoopDepth = 50
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Emit(core.Event{Type: "loop"})
+	d.Emit(core.Event{Type: "loop"})
+	d.Update(0)
+
+	if len(rec.events) != 50 {
+		t.Errorf("Expected the limit to apply per frame across events, got %d runs", len(rec.events))
+	}
+	if len(errs) != 2 || !errors.Is(errs[0], ErrLoopLimit) {
+		t.Fatalf("Expected 2 ErrLoopLimit reports, got %v", errs)
+	}
+
+	d.Emit(core.Event{Type: "loop"})
+	d.Update(0)
+	if len(rec.events) != 100 {
+		t.Errorf("The limit should reset each frame, got %d runs", len(rec.events))
+	}
+}
+
+// ============================================
+// Cascade Tests
+// ============================================
+
+// relay emits an event of type to when it runs, copying A and B.
+func relay(to core.EventType, d **Dispatcher) Action {
+	return funcAction(func(ctx *core.Context) {
+		(*d).Emit(core.Event{Type: to, A: ctx.Event.A, B: ctx.Event.B})
+	})
+}
+
+// chainDispatcher wires explosion → damage → death.
+func chainDispatcher(cascade bool) (*Dispatcher, *recordAction) {
+	died := &recordAction{}
+	var d *Dispatcher
+	d = NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "explode", Trigger: Trigger{Type: "explosion"}, Actions: []Action{relay("damage", &d)}},
+		{ID: "hurt", Trigger: Trigger{Type: "damage"}, Actions: []Action{relay("death", &d)}},
+		{ID: "die", Trigger: Trigger{Type: "death"}, Actions: []Action{died}},
+	})
+	d.Cascade = cascade
+	return d, died
+}
+
+func TestCascadeResolvesChainInOneUpdate(t *testing.T) {
+	d, died := chainDispatcher(true)
+
+	d.Emit(core.Event{Type: "explosion", A: 1})
+	d.Update(0)
+
+	if len(died.events) != 1 {
+		t.Fatalf("Expected death in the same frame, got %d", len(died.events))
+	}
+	if died.events[0].Depth != 2 {
+		t.Errorf("Expected depth 2, got %d", died.events[0].Depth)
+	}
+}
+
+func TestWithoutCascadeChainTakesAFramePerLink(t *testing.T) {
+	d, died := chainDispatcher(false)
+
+	d.Emit(core.Event{Type: "explosion", A: 1})
+	d.Update(0)
+	d.Update(0)
+	if len(died.events) != 0 {
+		t.Fatal("Death should not happen before the third frame")
+	}
+	d.Update(0)
+	if len(died.events) != 1 {
+		t.Errorf("Expected death on the third frame, got %d", len(died.events))
+	}
+}
+
+func TestCascadeDepthLimit(t *testing.T) {
+	d, died := chainDispatcher(true)
+	d.MaxCascadeDepth = 1
+	var errs []error
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Emit(core.Event{Type: "explosion", A: 1})
+	d.Update(0)
+	if len(died.events) != 0 || len(errs) != 1 || !errors.Is(errs[0], ErrCascadeDepth) {
+		t.Fatalf("Expected the death event deferred with ErrCascadeDepth, got %d deaths and %v", len(died.events), errs)
+	}
+
+	d.Update(0)
+	if len(died.events) != 1 || died.events[0].Depth != 0 {
+		t.Errorf("Deferred event should run next frame with a fresh depth, got %v", died.events)
+	}
+}
+
+func TestCascadeEventBudget(t *testing.T) {
+	rec := &recordAction{}
+	var d *Dispatcher
+	d = NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "fan_out", Trigger: Trigger{Type: "spark"}, Actions: []Action{rec, funcAction(func(ctx *core.Context) {
+			if n := ctx.Event.A; n < 8 { // binary tree of 15 sparks
+				d.Emit(core.Event{Type: "spark", A: 2 * n})
+				d.Emit(core.Event{Type: "spark", A: 2*n + 1})
+			}
+		})}},
+	})
+	d.Cascade = true
+	d.MaxCascadeEvents = 6 // the tick event counts too
+	var errs []error
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Emit(core.Event{Type: "spark", A: 1})
+	d.Update(0)
+	if len(rec.events) != 5 {
+		t.Fatalf("Expected 5 events within budget, got %d", len(rec.events))
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrCascadeLimit) {
+		t.Errorf("Expected one ErrCascadeLimit, got %v", errs)
+	}
+
+	d.MaxCascadeEvents = 0
+	d.Update(0)
+	if len(rec.events) != 15 {
+		t.Errorf("Deferred events should finish next frame, got %d total", len(rec.events))
+	}
+}
+
+func TestCascadeCycleDetection(t *testing.T) {
+	rec := &recordAction{}
+	var errs []error
+	var d *Dispatcher
+	d = NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "ping", Trigger: Trigger{Type: "ping"}, Actions: []Action{rec, relay("pong", &d)}},
+		{ID: "pong", Trigger: Trigger{Type: "pong"}, Actions: []Action{relay("ping", &d)}},
+	})
+	d.Cascade = true
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Emit(core.Event{Type: "ping", A: 1})
+	d.Update(0)
+	if len(rec.events) != 1 {
+		t.Errorf("ping should run once before the cycle is cut, ran %d times", len(rec.events))
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrCascadeCycle) {
+		t.Fatalf("Expected one ErrCascadeCycle, got %v", errs)
+	}
+
+	d.Update(0)
+	if len(rec.events) != 2 {
+		t.Errorf("The cyclic event should be retried next frame, ping ran %d times", len(rec.events))
+	}
+}
+
+func TestCascadeAllowsRepeatsOutsideChain(t *testing.T) {
+	rec := &recordAction{}
+	var errs []error
+	var d *Dispatcher
+	d = NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "explode", Trigger: Trigger{Type: "explosion"}, Actions: []Action{relay("damage", &d)}},
+		{ID: "hurt", Trigger: Trigger{Type: "damage"}, Actions: []Action{rec}},
+	})
+	d.Cascade = true
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Emit(core.Event{Type: "explosion", A: 1})
+	d.Emit(core.Event{Type: "explosion", A: 1})
+	d.Update(0)
+	if len(rec.events) != 2 || len(errs) != 0 {
+		t.Errorf("Independent chains are not cycles: %d damage, errors %v", len(rec.events), errs)
+	}
+}
+
+// ============================================
+// Priority Tests
+// ============================================
+
+// orderAction appends its name to a shared log.
+func orderAction(log *[]string, name string) Action {
+	return funcAction(func(ctx *core.Context) { *log = append(*log, name) })
+}
+
+func TestPriorityOrder(t *testing.T) {
+	var ran []string
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "low", Priority: -1, Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "low")}},
+		{ID: "first", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "first")}},
+		{ID: "high", Priority: 5, Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "high")}},
+		{ID: "second", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "second")}},
+	})
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+
+	want := []string{"high", "first", "second", "low"}
+	if !slices.Equal(ran, want) {
+		t.Errorf("Expected %v, got %v", want, ran)
+	}
+	if d.Behaviors[0].ID != "low" {
+		t.Error("Update should not reorder the Behaviors slice")
+	}
+}
+
+func TestConsumeStopsLowerPriorities(t *testing.T) {
+	var ran []string
+	consume := funcAction(func(ctx *core.Context) { ctx.Consume() })
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "damage", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "damage")}},
+		{ID: "shield", Priority: 10, Trigger: Trigger{Type: "hit"}, Actions: []Action{consume, orderAction(&ran, "shield")}},
+		{ID: "other", Trigger: Trigger{Type: "heal"}, Actions: []Action{orderAction(&ran, "heal")}},
+	})
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Emit(core.Event{Type: "heal"})
+	d.Update(0)
+
+	want := []string{"shield", "heal"}
+	if !slices.Equal(ran, want) {
+		t.Errorf("Expected %v, got %v", want, ran)
+	}
+}
+
+func TestConsumeOnlyWhenConditionsPass(t *testing.T) {
+	var ran []string
+	consume := funcAction(func(ctx *core.Context) { ctx.Consume() })
+	never := funcCondition(func(ctx *core.Context) bool { return false })
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "shield", Priority: 10, Trigger: Trigger{Type: "hit"}, Conditions: []Condition{never}, Actions: []Action{consume}},
+		{ID: "damage", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "damage")}},
+	})
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+	if !slices.Equal(ran, []string{"damage"}) {
+		t.Errorf("An inactive shield should not consume the hit, got %v", ran)
+	}
+}
+
+// ============================================
+// Runtime Management Tests
+// ============================================
+
+func TestAddBehavior(t *testing.T) {
+	var ran []string
+	d := NewDispatcher(entity.NewWorld(), nil)
+	if err := d.AddBehavior(&Behavior{ID: "a", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "a")}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddBehavior(&Behavior{ID: "a"}); err == nil {
+		t.Error("Adding a duplicate ID should fail")
+	}
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+	if !slices.Equal(ran, []string{"a"}) {
+		t.Errorf("Expected added behavior to run, got %v", ran)
+	}
+}
+
+func TestRemoveBehaviorDuringUpdate(t *testing.T) {
+	var ran []string
+	var d *Dispatcher
+	remover := funcAction(func(ctx *core.Context) { d.RemoveBehavior("later") })
+	d = NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "remover", Trigger: Trigger{Type: "hit"}, Actions: []Action{remover, orderAction(&ran, "remover")}},
+		{ID: "later", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "later")}},
+	})
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+	if !slices.Equal(ran, []string{"remover"}) {
+		t.Errorf("Removed behavior should not run for the event in flight, got %v", ran)
+	}
+	if d.Behavior("later") != nil || len(d.Behaviors) != 1 {
+		t.Error("Behavior should be gone")
+	}
+	if d.RemoveBehavior("later") {
+		t.Error("Removing twice should report false")
+	}
+}
+
+func TestAddBehaviorDuringUpdate(t *testing.T) {
+	var ran []string
+	var d *Dispatcher
+	adder := funcAction(func(ctx *core.Context) {
+		d.AddBehavior(&Behavior{ID: "new", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "new")}})
+	})
+	d = NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "adder", Trigger: Trigger{Type: "hit"}, Actions: []Action{adder}},
+	})
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+	if !slices.Equal(ran, []string{"new"}) {
+		t.Errorf("Added behavior should see the next event only, got %v", ran)
+	}
+}
+
+func TestSetEnabled(t *testing.T) {
+	var ran []string
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "a", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "a")}},
+	})
+
+	d.SetEnabled("a", false)
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+	d.SetEnabled("a", true)
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+
+	if len(ran) != 1 {
+		t.Errorf("Expected 1 run, got %d", len(ran))
+	}
+	if d.SetEnabled("missing", true) {
+		t.Error("SetEnabled on an unknown ID should report false")
+	}
+}
+
+func TestGroupToggle(t *testing.T) {
+	var ran []string
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "p1", Group: "boss_phase_1", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "p1")}},
+		{ID: "p2", Group: "boss_phase_2", Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "p2")}},
+		{ID: "p2b", Group: "boss_phase_2", Disabled: true, Trigger: Trigger{Type: "hit"}, Actions: []Action{orderAction(&ran, "p2b")}},
+	})
+	d.SetGroupEnabled("boss_phase_2", false)
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+	d.SetGroupEnabled("boss_phase_1", false)
+	d.SetGroupEnabled("boss_phase_2", true)
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+
+	want := []string{"p1", "p2"}
+	if !slices.Equal(ran, want) {
+		t.Errorf("Expected %v, got %v", want, ran)
+	}
+	if d.GroupEnabled("boss_phase_1") {
+		t.Error("boss_phase_1 should report disabled")
+	}
+}
+
+func TestDisabledTimerKeepsProgress(t *testing.T) {
+	rec := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "t", Trigger: Trigger{Type: "timer", Interval: 100}, Actions: []Action{rec}},
+	})
+
+	d.Update(60 * time.Millisecond)
+	d.SetEnabled("t", false)
+	runFrames(d, 10, 100*time.Millisecond)
+	d.SetEnabled("t", true)
+	d.Update(40 * time.Millisecond)
+	if len(rec.events) != 1 {
+		t.Errorf("Expected exactly one fire after re-enabling, got %d", len(rec.events))
+	}
+
+	d.RemoveBehavior("t")
+	d.AddBehavior(&Behavior{ID: "t", Trigger: Trigger{Type: "timer", Interval: 100}, Actions: []Action{rec}})
+	d.Update(60 * time.Millisecond)
+	if len(rec.events) != 1 {
+		t.Error("A re-added timer should start from scratch")
+	}
+}
+
+// ============================================
+// Payload Schema Tests
+// ============================================
+
+func TestEmitValidatesPayloadInDebugBuilds(t *testing.T) {
+	rec := &recordAction{}
+	var errs []error
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "hurt", Trigger: Trigger{Type: "damage"}, Actions: []Action{rec}},
+	})
+	d.OnError = func(err error) { errs = append(errs, err) }
+	d.RegisterPayload("damage", core.PayloadSchema{{Key: "amount", Type: core.PayloadNumber, Required: true}})
+
+	d.Emit(core.Event{Type: "damage", Payload: map[string]any{"amount": 5}})
+	d.Emit(core.Event{Type: "damage", Payload: map[string]any{"amount": "lots"}})
+	d.Update(0)
+
+	want := 0
+	if debugBuild {
+		want = 1
+	}
+	if len(errs) != want {
+		t.Errorf("Expected %d payload errors (debug=%v), got %v", want, debugBuild, errs)
+	}
+	if len(rec.events) != 2 {
+		t.Error("Invalid events should still be delivered")
+	}
+}
+
+func TestTimerEventsAreValidated(t *testing.T) {
+	var errs []error
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "t", Trigger: Trigger{Type: "timer", Interval: 1, Ticks: true}, Actions: []Action{&recordAction{}}},
+	})
+	d.OnError = func(err error) { errs = append(errs, err) }
+	d.RegisterPayload(core.EventTimer, core.PayloadSchema{{Key: "phase", Type: core.PayloadString, Required: true}})
+
+	d.Update(0)
+
+	want := 0
+	if debugBuild {
+		want = 1
+	}
+	if len(errs) != want {
+		t.Errorf("Expected %d payload errors for the timer event (debug=%v), got %v", want, debugBuild, errs)
+	}
+}
+
+func TestBuiltinPayloadsMatchSchemas(t *testing.T) {
+	world := entity.NewWorld()
+	rec := &recordAction{}
+	d := NewDispatcher(world, []*Behavior{
+		{ID: "tick", Trigger: Trigger{Type: "tick"}, Actions: []Action{rec}},
+		{ID: "timer", Trigger: Trigger{Type: "timer", Interval: 1, Ticks: true}, Actions: []Action{rec}},
+		{ID: "tag", Trigger: Trigger{Type: "tag_added"}, Actions: []Action{rec}},
+	})
+	d.EmitTagEvents()
+	world.CreateEntity("enemy")
+	d.Update(time.Millisecond)
+
+	if len(rec.events) != 3 {
+		t.Fatalf("Expected tick, timer and tag_added, got %d events", len(rec.events))
+	}
+	for _, ev := range rec.events {
+		schema, ok := d.PayloadSchema(ev.Type)
+		if !ok {
+			t.Errorf("No schema for %q", ev.Type)
+			continue
+		}
+		if err := schema.Validate(ev); err != nil {
+			t.Errorf("%q: %v", ev.Type, err)
+		}
+	}
+}
+
+// ============================================
+// Action Error Tests
+// ============================================
+
+// errAction always fails with err.
+type errAction struct{ err error }
+
+func (a errAction) Execute(ctx *core.Context) error {
+	return a.err
+}
+
+// panicAction always panics.
+type panicAction struct{}
+
+func (panicAction) Execute(ctx *core.Context) error {
+	panic("boom")
+}
+
+func TestActionErrorStopsBehavior(t *testing.T) {
+	missing := errors.New("missing component health")
+	rec := &recordAction{}
+	other := &recordAction{}
+	var errs []error
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "broken", Trigger: Trigger{Type: "hit"}, Actions: []Action{errAction{missing}, rec}},
+		{ID: "fine", Trigger: Trigger{Type: "hit"}, Actions: []Action{other}},
+	})
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Emit(core.Event{Type: "hit", A: 7})
+	d.Update(0)
+
+	if len(rec.events) != 0 {
+		t.Error("Actions after a failing one should not run")
+	}
+	if len(other.events) != 1 {
+		t.Error("Other behaviors should still run")
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], missing) {
+		t.Fatalf("Expected the action error reported, got %v", errs)
+	}
+	want := `behavior "broken": actions[0] (event "hit"): missing component health`
+	if errs[0].Error() != want {
+		t.Errorf("Expected %q, got %q", want, errs[0].Error())
+	}
+
+	failures := d.Failures("broken")
+	if len(failures) != 1 || failures[0].Event.A != 7 || failures[0].Action != 0 {
+		t.Errorf("Expected one recorded failure with its event, got %v", failures)
+	}
+}
+
+func TestActionPanicRecovered(t *testing.T) {
+	var errs []error
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "crashy", Trigger: Trigger{Type: "hit"}, Actions: []Action{&recordAction{}, panicAction{}}},
+	})
+	d.OnError = func(err error) { errs = append(errs, err) }
+
+	d.Emit(core.Event{Type: "hit"})
+	d.Update(0)
+
+	var be *BehaviorError
+	if len(errs) != 1 || !errors.As(errs[0], &be) {
+		t.Fatalf("Expected a BehaviorError, got %v", errs)
+	}
+	if be.Panic != "boom" || be.Action != 1 {
+		t.Errorf("Expected panic \"boom\" in actions[1], got %v in actions[%d]", be.Panic, be.Action)
+	}
+}
+
+func TestFailuresBookkeeping(t *testing.T) {
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "b", Trigger: Trigger{Type: "hit"}, Actions: []Action{errAction{errors.New("nope")}}},
+		{ID: "a", Trigger: Trigger{Type: "loop"}, Actions: []Action{&recordAction{}}},
+	})
+	d.OnError = func(error) {}
+	d.MaxLoopDepth = 1
+
+	for i := 0; i < maxFailures+5; i++ {
+		d.Emit(core.Event{Type: "hit"})
+	}
+	d.Emit(core.Event{Type: "loop"})
+	d.Update(0)
+
+	if n := len(d.Failures("b")); n != maxFailures {
+		t.Errorf("Expected failures capped at %d, got %d", maxFailures, n)
+	}
+	if ids := d.FailedBehaviors(); !slices.Equal(ids, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", ids)
+	}
+	if f := d.Failures("a"); len(f) != 1 || !errors.Is(f[0], ErrLoopLimit) || f[0].Action != -1 {
+		t.Errorf("Loop limit should be recorded as a failure, got %v", f)
+	}
+
+	d.ClearFailures("b")
+	if len(d.Failures("b")) != 0 {
+		t.Error("ClearFailures should forget failures")
+	}
+}
+
+// ============================================
+// Context Access Tests
+// ============================================
+
+func TestContextRandIsSeeded(t *testing.T) {
+	roll := func(seed uint64) []int {
+		var rolls []int
+		d := NewDispatcher(entity.NewWorld(), []*Behavior{
+			{ID: "dice", Trigger: Trigger{Type: "tick"}, Actions: []Action{funcAction(func(ctx *core.Context) {
+				rolls = append(rolls, ctx.Rand.IntN(1000))
+			})}},
+		})
+		d.Seed(seed)
+		runFrames(d, 5, 0)
+		return rolls
+	}
+
+	if a, b := roll(7), roll(7); !slices.Equal(a, b) {
+		t.Errorf("Same seed should give the same rolls: %v vs %v", a, b)
+	}
+	if a, b := roll(7), roll(8); slices.Equal(a, b) {
+		t.Errorf("Different seeds should give different rolls: %v", a)
+	}
+}
+
+func TestContextDeltaAndEmit(t *testing.T) {
+	var deltas []time.Duration
+	got := &recordAction{}
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "hit", Trigger: Trigger{Type: "hit"}, Actions: []Action{funcAction(func(ctx *core.Context) {
+			deltas = append(deltas, ctx.Delta)
+			if err := ctx.Emit(core.Event{Type: "hurt", A: ctx.Event.A}); err != nil {
+				t.Errorf("Emit failed: %v", err)
+			}
+		})}},
+		{ID: "hurt", Trigger: Trigger{Type: "hurt"}, Actions: []Action{got}},
+	})
+
+	d.Emit(core.Event{Type: "hit", A: 3})
+	d.Update(16 * time.Millisecond)
+	d.Update(20 * time.Millisecond)
+
+	if !slices.Equal(deltas, []time.Duration{16 * time.Millisecond}) {
+		t.Errorf("Expected the frame delta, got %v", deltas)
+	}
+	if len(got.events) != 1 || got.events[0].A != 3 {
+		t.Errorf("ctx.Emit should queue on the dispatcher, got %v", got.events)
+	}
+}
+
+func TestContextScratchPerBehavior(t *testing.T) {
+	counter := funcAction(func(ctx *core.Context) {
+		n, _ := ctx.Scratch["n"].(int)
+		ctx.Scratch["n"] = n + 1
+	})
+	var seen []int
+	peek := funcAction(func(ctx *core.Context) {
+		n, _ := ctx.Scratch["n"].(int)
+		seen = append(seen, n)
+	})
+	d := NewDispatcher(entity.NewWorld(), []*Behavior{
+		{ID: "count", Priority: 1, Trigger: Trigger{Type: "tick"}, Actions: []Action{counter, peek}},
+		{ID: "other", Trigger: Trigger{Type: "tick"}, Actions: []Action{peek}},
+	})
+
+	runFrames(d, 3, 0)
+	if !slices.Equal(seen, []int{1, 0, 2, 0, 3, 0}) {
+		t.Errorf("Scratch should persist per behavior, got %v", seen)
+	}
+
+	b := d.Behavior("count")
+	d.RemoveBehavior("count")
+	if err := d.AddBehavior(b); err != nil {
+		t.Fatal(err)
+	}
+	seen = nil
+	runFrames(d, 1, 0)
+	if seen[0] != 1 {
+		t.Errorf("Removing a behavior should drop its scratch, got %v", seen)
+	}
+}
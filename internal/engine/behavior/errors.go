@@ -0,0 +1,68 @@
+package behavior
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+)
+
+// maxFailures is how many recent failures Dispatcher.Failures keeps per
+// behavior.
+const maxFailures = 32
+
+// BehaviorError reports a behavior that failed while handling an event.
+type BehaviorError struct {
+	BehaviorID string
+	Event      core.Event // the event the behavior was running for
+	Action     int        // index in Behavior.Actions, -1 if no action failed
+	Err        error
+	Panic      any // the recovered value if the action panicked
+}
+
+func (e *BehaviorError) Error() string {
+	if e.Action < 0 {
+		return fmt.Sprintf("behavior %q (event %q): %v", e.BehaviorID, e.Event.Type, e.Err)
+	}
+	return fmt.Sprintf("behavior %q: actions[%d] (event %q): %v", e.BehaviorID, e.Action, e.Event.Type, e.Err)
+}
+
+func (e *BehaviorError) Unwrap() error {
+	return e.Err
+}
+
+// fail records err against its behavior and reports it.
+func (d *Dispatcher) fail(err *BehaviorError) {
+	if d.failures == nil {
+		d.failures = make(map[string][]*BehaviorError)
+	}
+	list := append(d.failures[err.BehaviorID], err)
+	if len(list) > maxFailures {
+		list = slices.Delete(list, 0, len(list)-maxFailures)
+	}
+	d.failures[err.BehaviorID] = list
+	d.reportError(err)
+}
+
+// Failures returns the most recent failures of the behavior with the given
+// ID, oldest first.
+func (d *Dispatcher) Failures(id string) []*BehaviorError {
+	return slices.Clone(d.failures[id])
+}
+
+// FailedBehaviors lists the IDs of behaviors with recorded failures, sorted,
+// so tools can flag broken rules.
+func (d *Dispatcher) FailedBehaviors() []string {
+	ids := make([]string, 0, len(d.failures))
+	for id := range d.failures {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// ClearFailures forgets the recorded failures of the behavior with the given
+// ID.
+func (d *Dispatcher) ClearFailures(id string) {
+	delete(d.failures, id)
+}
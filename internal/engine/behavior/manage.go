@@ -0,0 +1,84 @@
+package behavior
+
+import (
+	"fmt"
+	"slices"
+)
+
+// The methods in this file change the set of behaviors at runtime. They are
+// safe to call from actions during Update: changes apply from the next event
+// the dispatcher processes, except that a removed or disabled behavior never
+// runs again, even for the event in flight.
+
+// Behavior returns the behavior with the given ID, or nil.
+func (d *Dispatcher) Behavior(id string) *Behavior {
+	for _, b := range d.Behaviors {
+		if b.ID == id {
+			return b
+		}
+	}
+	return nil
+}
+
+// AddBehavior appends b. IDs must be unique.
+func (d *Dispatcher) AddBehavior(b *Behavior) error {
+	if d.Behavior(b.ID) != nil {
+		return fmt.Errorf("behavior: duplicate behavior id %q", b.ID)
+	}
+	b.removed = false
+	d.Behaviors = append(slices.Clip(d.Behaviors), b)
+	d.sortBehaviors()
+	return nil
+}
+
+// RemoveBehavior removes the behavior with the given ID and drops its timer
+// state and scratch store. It reports whether the behavior existed.
+func (d *Dispatcher) RemoveBehavior(id string) bool {
+	i := slices.IndexFunc(d.Behaviors, func(b *Behavior) bool { return b.ID == id })
+	if i < 0 {
+		return false
+	}
+	b := d.Behaviors[i]
+	b.removed = true
+	d.Behaviors = slices.Delete(slices.Clone(d.Behaviors), i, i+1)
+	delete(d.timers, b)
+	delete(d.loopDepth, b)
+	delete(d.scratch, b)
+	d.sortBehaviors()
+	return true
+}
+
+// SetEnabled enables or disables the behavior with the given ID. Disabled
+// timers keep their progress. It reports whether the behavior exists.
+func (d *Dispatcher) SetEnabled(id string, enabled bool) bool {
+	b := d.Behavior(id)
+	if b == nil {
+		return false
+	}
+	b.Disabled = !enabled
+	return true
+}
+
+// SetGroupEnabled enables or disables every behavior whose Group is group,
+// including behaviors added to the group later. A behavior runs only if both
+// it and its group are enabled.
+func (d *Dispatcher) SetGroupEnabled(group string, enabled bool) {
+	if enabled {
+		delete(d.disabledGroups, group)
+		return
+	}
+	if d.disabledGroups == nil {
+		d.disabledGroups = make(map[string]bool)
+	}
+	d.disabledGroups[group] = true
+}
+
+// GroupEnabled reports whether group is enabled. Groups start enabled.
+func (d *Dispatcher) GroupEnabled(group string) bool {
+	return !d.disabledGroups[group]
+}
+
+// active reports whether b may run.
+func (d *Dispatcher) active(b *Behavior) bool {
+	return !b.Disabled && !b.removed && (b.Group == "" || !d.disabledGroups[b.Group])
+}
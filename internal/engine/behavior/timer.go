@@ -0,0 +1,91 @@
+package behavior
+
+import (
+	"time"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+)
+
+// timer is the scheduling state of one timer behavior.
+type timer struct {
+	elapsed time.Duration // time (or ticks) since the last fire
+	fired   int
+	done    bool
+}
+
+// unit is the length of one Interval/Delay step. In tick mode each Update
+// advances elapsed by exactly one unit.
+func (t Trigger) unit() time.Duration {
+	if t.Ticks {
+		return 1
+	}
+	return time.Millisecond
+}
+
+// next returns how long to wait for fire number fired+1.
+func (t Trigger) next(fired int) time.Duration {
+	if fired == 0 && t.Delay > 0 {
+		return time.Duration(t.Delay) * t.unit()
+	}
+	return time.Duration(t.Interval) * t.unit()
+}
+
+// advanceTimers moves every timer behavior forward by dt and runs the ones
+// that came due. A long frame fires a timer once per elapsed period, so
+// behavior does not depend on the frame rate.
+func (d *Dispatcher) advanceTimers(dt time.Duration) {
+	for _, b := range d.order {
+		t := b.Trigger
+		if t.Type != TriggerTimer {
+			continue
+		}
+		if d.timers == nil {
+			d.timers = make(map[*Behavior]*timer)
+		}
+		st := d.timers[b]
+		if st == nil {
+			st = &timer{}
+			d.timers[b] = st
+		}
+		if st.done || !d.active(b) {
+			continue // disabled timers keep their progress
+		}
+
+		if t.Ticks {
+			st.elapsed++
+		} else {
+			st.elapsed += dt
+		}
+
+		for due := t.next(st.fired); !st.done && d.active(b) && st.elapsed >= due; due = t.next(st.fired) {
+			st.elapsed -= due
+			st.fired++
+			st.done = t.Interval <= 0 || (t.Repeat > 0 && st.fired >= t.Repeat)
+			ev := core.Event{
+				Type: core.EventTimer,
+				Payload: map[string]any{
+					"behavior": b.ID,
+					"count":    st.fired,
+				},
+			}
+			d.validatePayload(ev) // timer events bypass Emit
+			d.runBehavior(b, ev)
+		}
+	}
+}
+
+// Pause stops timers and event processing until Resume. Events emitted while
+// paused stay queued.
+func (d *Dispatcher) Pause() {
+	d.paused = true
+}
+
+// Resume undoes Pause.
+func (d *Dispatcher) Resume() {
+	d.paused = false
+}
+
+// Paused reports whether the dispatcher is paused.
+func (d *Dispatcher) Paused() bool {
+	return d.paused
+}
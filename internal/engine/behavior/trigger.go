@@ -1,31 +1,161 @@
 package behavior
 
-import "github.com/GiannisPettas/ember2D/internal/engine/core"
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Trigger types with special handling in the Dispatcher.
+const (
+	// TriggerTimer is scheduled by the Dispatcher itself.
+	TriggerTimer = string(core.EventTimer)
+	// TriggerLoop re-runs its behavior within the frame, see Trigger.Count.
+	TriggerLoop = "loop"
+)
 
 // Trigger defines when a behavior should run.
+//
+// Timer triggers are driven by Dispatcher.Update rather than by emitted
+// events: the first fire happens after Delay (or Interval when Delay is 0),
+// then every Interval until Repeat fires have happened. A timer without an
+// Interval fires once, after Delay. Examples:
+//
+//	Trigger{Type: "timer", Interval: 2000}          // every 2s, forever
+//	Trigger{Type: "timer", Delay: 500}              // once, after 0.5s
+//	Trigger{Type: "timer", Interval: 10, Ticks: true, Repeat: 3}
+//
+// Loop triggers run their behavior repeatedly for one "loop" event, with
+// ctx.Event.Loop counting iterations: Count times, or until Until holds
+// (checked before each iteration). A loop with neither is invalid and is
+// rejected by the rule loader; built in Go, it runs until the per-frame cap
+// Dispatcher.MaxLoopDepth and is reported as ErrLoopLimit.
+// Timer fields on other trigger types, and loop fields on anything but a
+// loop, are ignored here; the rule loader rejects them.
+//
+// A and B constrain each participant separately with a pattern: a tag
+// (hierarchical, so "enemy" matches "enemy.flying"), a role name, "*" for any
+// participant, or a glob such as "enemy.*" or "boss_?". With AnyOrder the
+// participants may arrive either way round; the behavior then sees them
+// swapped so that ctx.Event.A always matches A:
+//
+//	Trigger{Type: "collision", A: "player", B: "enemy", AnyOrder: true}
 type Trigger struct {
-	Type     string   // e.g. "start", "collision", "timer"
-	Entities []string // optional: entity ids or roles
-	Interval int      // used for timers (future)
+	Type     string          // e.g. "start", "collision", "timer"
+	Entities []string        // optional: patterns that A or B must match
+	A        string          // optional: pattern participant A must match
+	B        string          // optional: pattern participant B must match
+	AnyOrder bool            // also accept A and B swapped
+	IDs      []entity.Entity // optional: entities that must be A or B
+	Interval int             // timer period in milliseconds (ticks if Ticks is set); 0 = one-shot
+	Delay    int             // timer: wait before the first fire, same unit as Interval
+	Repeat   int             // timer: number of fires before stopping; 0 = forever (1 if Interval is 0)
+	Ticks    bool            // timer: count Update calls instead of milliseconds
+	Count    int             // loop: number of iterations; 0 = until Until holds (invalid without Until)
+	Until    Condition       // loop: optional stop condition
+}
+
+// Matches reports whether ev should run the behavior, see Match.
+func (t Trigger) Matches(ev core.Event, world *entity.World) bool {
+	_, ok := t.Match(ev, world)
+	return ok
 }
 
-func (t Trigger) Matches(ev core.Event) bool {
+// Match reports whether ev should run the behavior and returns the event in
+// the trigger's canonical order, with A/B and their roles swapped if the
+// participants only matched the other way round (AnyOrder).
+//
+// Besides the type, every filter that is set must hold: the same participant,
+// A or B, must be both listed in IDs and match a pattern in Entities, and each
+// participant must match its own A or B pattern. Tags are checked through world's TagManager;
+// world may be nil, in which case only roles are consulted.
+func (t Trigger) Match(ev core.Event, world *entity.World) (core.Event, bool) {
 	// Type check
 	if string(ev.Type) != t.Type {
-		return false
+		return ev, false
 	}
 
-	// No entity filter → always matches
-	if len(t.Entities) == 0 {
-		return true
+	// Either-participant filters
+	if (len(t.IDs) > 0 || len(t.Entities) > 0) &&
+		!t.selects(ev.A, ev.RoleA, world) && !t.selects(ev.B, ev.RoleB, world) {
+		return ev, false
+	}
+
+	// Per-participant patterns
+	if t.A == "" && t.B == "" {
+		return ev, true
+	}
+	if matchPattern(t.A, ev.A, ev.RoleA, world) && matchPattern(t.B, ev.B, ev.RoleB, world) {
+		return ev, true
+	}
+	if t.AnyOrder && matchPattern(t.A, ev.B, ev.RoleB, world) && matchPattern(t.B, ev.A, ev.RoleA, world) {
+		ev.A, ev.B = ev.B, ev.A
+		ev.RoleA, ev.RoleB = ev.RoleB, ev.RoleA
+		return ev, true
+	}
+	return ev, false
+}
+
+// selects reports whether one participant passes both IDs and Entities.
+func (t Trigger) selects(e entity.Entity, role string, world *entity.World) bool {
+	return (len(t.IDs) == 0 || t.listed(e)) && (len(t.Entities) == 0 || t.matchesAny(e, role, world))
+}
+
+func (t Trigger) listed(e entity.Entity) bool {
+	return e != entity.Null && slices.Contains(t.IDs, e)
+}
+
+func (t Trigger) matchesAny(e entity.Entity, role string, world *entity.World) bool {
+	for _, pattern := range t.Entities {
+		if matchPattern(pattern, e, role, world) {
+			return true
+		}
 	}
+	return false
+}
 
-	// Collision / pair events: check against A/B
-	for _, e := range t.Entities {
-		if ev.A == e || ev.B == e {
+// matchPattern matches a participant against a tag, role, "*" or glob
+// pattern. The empty pattern matches anything, including no participant.
+func matchPattern(pattern string, e entity.Entity, role string, world *entity.World) bool {
+	switch {
+	case pattern == "":
+		return true
+	case pattern == "*":
+		return e != entity.Null || role != ""
+	case !isGlob(pattern):
+		if role != "" && role == pattern {
 			return true
 		}
+		return e != entity.Null && world != nil && world.Tags().HasTag(e, pattern)
 	}
 
+	if ok, _ := path.Match(pattern, role); ok && role != "" {
+		return true
+	}
+	if e == entity.Null || world == nil {
+		return false
+	}
+	// Like HasTag, "enemy.flying" also counts as its ancestor "enemy".
+	pattern = strings.ToLower(pattern)
+	for _, tag := range world.Tags().GetTags(e) {
+		for {
+			if ok, _ := path.Match(pattern, tag); ok {
+				return true
+			}
+			i := strings.LastIndexByte(tag, '.')
+			if i < 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+	}
 	return false
 }
+
+// isGlob reports whether a participant pattern uses glob syntax.
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
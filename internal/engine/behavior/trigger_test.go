@@ -0,0 +1,173 @@
+package behavior
+
+import (
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// ============================================
+// Participant Matching Tests
+// ============================================
+
+func TestMatchesWithoutFilter(t *testing.T) {
+	trig := Trigger{Type: "collision"}
+	if !trig.Matches(core.Event{Type: "collision"}, nil) {
+		t.Error("Unfiltered trigger should match its type")
+	}
+	if trig.Matches(core.Event{Type: "timer"}, nil) {
+		t.Error("Trigger should not match another type")
+	}
+}
+
+func TestMatchesByID(t *testing.T) {
+	world := entity.NewWorld()
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	trig := Trigger{Type: "collision", IDs: []entity.Entity{enemy}}
+
+	if !trig.Matches(core.Event{Type: "collision", A: player, B: enemy}, world) {
+		t.Error("Should match when B is listed")
+	}
+	if trig.Matches(core.Event{Type: "collision", A: player}, world) {
+		t.Error("Should not match when neither participant is listed")
+	}
+}
+
+func TestMatchesByTag(t *testing.T) {
+	world := entity.NewWorld()
+	player := world.CreateEntity("player")
+	bat := world.CreateEntity("enemy.flying.bat")
+	coin := world.CreateEntity("pickup")
+	trig := Trigger{Type: "collision", Entities: []string{"enemy"}}
+
+	if !trig.Matches(core.Event{Type: "collision", A: player, B: bat}, world) {
+		t.Error("Should match a descendant tag through the TagManager")
+	}
+	if trig.Matches(core.Event{Type: "collision", A: player, B: coin}, world) {
+		t.Error("Should not match when no participant has the tag")
+	}
+	if trig.Matches(core.Event{Type: "collision", A: player, B: bat}, nil) {
+		t.Error("Tags cannot match without a world")
+	}
+}
+
+func TestMatchesByRole(t *testing.T) {
+	trig := Trigger{Type: "hit", Entities: []string{"attacker"}}
+
+	if !trig.Matches(core.Event{Type: "hit", A: 1, RoleA: "attacker"}, nil) {
+		t.Error("Should match RoleA")
+	}
+	if trig.Matches(core.Event{Type: "hit", A: 1, RoleA: "target"}, nil) {
+		t.Error("Should not match a different role")
+	}
+}
+
+func TestIDsAndEntitiesApplyToOneParticipant(t *testing.T) {
+	world := entity.NewWorld()
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	boss := world.CreateEntity("enemy")
+	trig := Trigger{Type: "hit", IDs: []entity.Entity{player, boss}, Entities: []string{"enemy"}}
+
+	if trig.Matches(core.Event{Type: "hit", A: player, B: enemy}, world) {
+		t.Error("A listed and B tagged should not match")
+	}
+	if !trig.Matches(core.Event{Type: "hit", A: player, B: boss}, world) {
+		t.Error("B both listed and tagged should match")
+	}
+}
+
+func TestNullParticipantNeverMatchesTags(t *testing.T) {
+	world := entity.NewWorld()
+	trig := Trigger{Type: "hit", IDs: []entity.Entity{entity.Null}}
+
+	if trig.Matches(core.Event{Type: "hit"}, world) {
+		t.Error("An empty participant should not match")
+	}
+}
+
+// ============================================
+// Pattern Matching Tests
+// ============================================
+
+func TestMatchesPairInOrder(t *testing.T) {
+	world := entity.NewWorld()
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	trig := Trigger{Type: "collision", A: "player", B: "enemy"}
+
+	if !trig.Matches(core.Event{Type: "collision", A: player, B: enemy}, world) {
+		t.Error("Should match player/enemy")
+	}
+	if trig.Matches(core.Event{Type: "collision", A: enemy, B: player}, world) {
+		t.Error("Should not match swapped participants without AnyOrder")
+	}
+}
+
+func TestMatchAnyOrderSwaps(t *testing.T) {
+	world := entity.NewWorld()
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	trig := Trigger{Type: "collision", A: "player", B: "enemy", AnyOrder: true}
+
+	ev, ok := trig.Match(core.Event{Type: "collision", A: enemy, B: player, RoleA: "attacker", RoleB: "target"}, world)
+	if !ok {
+		t.Fatal("Should match in either order")
+	}
+	if ev.A != player || ev.B != enemy || ev.RoleA != "target" || ev.RoleB != "attacker" {
+		t.Errorf("Expected participants swapped into canonical order, got %+v", ev)
+	}
+
+	ev, _ = trig.Match(core.Event{Type: "collision", A: player, B: enemy}, world)
+	if ev.A != player {
+		t.Error("Already canonical events should not be swapped")
+	}
+}
+
+func TestMatchWildcards(t *testing.T) {
+	world := entity.NewWorld()
+	player := world.CreateEntity("player")
+	bat := world.CreateEntity("enemy.flying.bat")
+	boss := world.CreateEntity("boss_2")
+	cases := []struct {
+		trig Trigger
+		ev   core.Event
+		want bool
+	}{
+		{Trigger{Type: "hit", A: "player", B: "*"}, core.Event{Type: "hit", A: player, B: bat}, true},
+		{Trigger{Type: "hit", A: "player", B: "*"}, core.Event{Type: "hit", A: player}, false},
+		{Trigger{Type: "hit", B: "enemy.*"}, core.Event{Type: "hit", A: player, B: bat}, true},
+		{Trigger{Type: "hit", B: "enemy"}, core.Event{Type: "hit", A: player, B: bat}, true},
+		{Trigger{Type: "hit", B: "boss_?"}, core.Event{Type: "hit", A: player, B: boss}, true},
+		{Trigger{Type: "hit", B: "boss_?"}, core.Event{Type: "hit", A: player, B: bat}, false},
+		{Trigger{Type: "hit", B: "enem?"}, core.Event{Type: "hit", A: player, B: bat}, true},
+		{Trigger{Type: "hit", B: "*.flying"}, core.Event{Type: "hit", A: player, B: bat}, true},
+		{Trigger{Type: "hit", B: "flying*"}, core.Event{Type: "hit", A: player, B: bat}, false},
+		{Trigger{Type: "hit", A: "att*"}, core.Event{Type: "hit", A: player, RoleA: "attacker"}, true},
+		{Trigger{Type: "hit", Entities: []string{"enemy.*"}}, core.Event{Type: "hit", A: bat, B: player}, true},
+	}
+
+	for i, tc := range cases {
+		if got := tc.trig.Matches(tc.ev, world); got != tc.want {
+			t.Errorf("case %d: %+v: expected %v, got %v", i, tc.trig, tc.want, got)
+		}
+	}
+}
+
+func TestDispatcherSeesCanonicalOrder(t *testing.T) {
+	world := entity.NewWorld()
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	rec := &recordAction{}
+	d := NewDispatcher(world, []*Behavior{
+		{ID: "hit", Trigger: Trigger{Type: "collision", A: "player", B: "enemy", AnyOrder: true}, Actions: []Action{rec}},
+	})
+
+	d.Emit(core.Event{Type: "collision", A: enemy, B: player})
+	d.Update(0)
+	if len(rec.events) != 1 || rec.events[0].A != player || rec.events[0].B != enemy {
+		t.Errorf("Expected one event with A=player, got %+v", rec.events)
+	}
+}
@@ -0,0 +1,39 @@
+package components
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// MarshalComponent encodes the entity's component as JSON.
+// It is used by the scene saver through the World's component registry.
+func (cm *ComponentManager[T]) MarshalComponent(e entity.Entity) ([]byte, error) {
+	component := cm.Get(e)
+	if component == nil {
+		return nil, fmt.Errorf("entity %d has no %T component", e, *new(T))
+	}
+	return json.Marshal(component)
+}
+
+// UnmarshalComponent decodes JSON into a new component and attaches it to the
+// entity, overwriting any existing one. Unknown fields are rejected so typos
+// in hand-written files are caught.
+func (cm *ComponentManager[T]) UnmarshalComponent(e entity.Entity, data []byte) error {
+	var component T
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&component); err != nil {
+		return err
+	}
+	cm.Add(e, component)
+	return nil
+}
+
+// Type returns the Go type of the component, used to generate scene schemas.
+func (cm *ComponentManager[T]) Type() reflect.Type {
+	return reflect.TypeFor[T]()
+}
@@ -2,7 +2,9 @@ package components
 
 // Display defines how an entity appears on screen.
 type Display struct {
-	Width   float64
-	Height  float64
-	R, G, B uint8
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	R      uint8   `json:"r"`
+	G      uint8   `json:"g"`
+	B      uint8   `json:"b"`
 }
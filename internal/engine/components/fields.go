@@ -0,0 +1,149 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Field gives rule files access to one field of component type T by name,
+// e.g. "current" in "A.health.current". Build fields with NumberField,
+// StringField and BoolField and pass them to Register or DefineFields.
+type Field[T any] struct {
+	Name string
+	Get  func(c *T) any
+	Set  func(c *T, v any) error // nil for a read-only field
+}
+
+// Number is the set of field types NumberField accepts.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// NumberField exposes the numeric field ptr points at. Set accepts any Go
+// number; integer fields only take values they can hold exactly.
+//
+// Usage:
+//
+//	components.NumberField("current", func(h *Health) *int { return &h.Current })
+func NumberField[T any, V Number](name string, ptr func(c *T) *V) Field[T] {
+	return Field[T]{
+		Name: name,
+		Get:  func(c *T) any { return *ptr(c) },
+		Set: func(c *T, v any) error {
+			f, ok := core.ToFloat(v)
+			if !ok {
+				return fmt.Errorf("expected a number, got %T", v)
+			}
+			n := V(f)
+			half := 0.5
+			if V(half) == 0 && float64(n) != f { // integer field
+				return fmt.Errorf("%v does not fit in %T", f, n)
+			}
+			*ptr(c) = n
+			return nil
+		},
+	}
+}
+
+// StringField exposes the string field ptr points at.
+func StringField[T any, V ~string](name string, ptr func(c *T) *V) Field[T] {
+	return Field[T]{
+		Name: name,
+		Get:  func(c *T) any { return *ptr(c) },
+		Set: func(c *T, v any) error {
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("expected a string, got %T", v)
+			}
+			*ptr(c) = V(s)
+			return nil
+		},
+	}
+}
+
+// BoolField exposes the bool field ptr points at.
+func BoolField[T any, V ~bool](name string, ptr func(c *T) *V) Field[T] {
+	return Field[T]{
+		Name: name,
+		Get:  func(c *T) any { return *ptr(c) },
+		Set: func(c *T, v any) error {
+			b, ok := v.(bool)
+			if !ok {
+				return fmt.Errorf("expected a bool, got %T", v)
+			}
+			*ptr(c) = V(b)
+			return nil
+		},
+	}
+}
+
+// DefineFields adds fields to the ones GetField and SetField can reach.
+// Names are case-insensitive and may contain dots for nested data, e.g.
+// "stats.max". Defining a name twice panics.
+func (cm *ComponentManager[T]) DefineFields(fields ...Field[T]) {
+	if cm.fields == nil {
+		cm.fields = make(map[string]Field[T], len(fields))
+	}
+	for _, f := range fields {
+		name := strings.ToLower(f.Name)
+		if _, exists := cm.fields[name]; exists {
+			panic(fmt.Sprintf("components: field %q of %T defined twice", f.Name, *new(T)))
+		}
+		cm.fields[name] = f
+	}
+}
+
+// FieldNames returns the defined field names, sorted.
+func (cm *ComponentManager[T]) FieldNames() []string {
+	names := make([]string, 0, len(cm.fields))
+	for name := range cm.fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GetField reads a field of the entity's component by its defined name,
+// ignoring case, so rule files can say "Health.Current" or "health.current"
+// alike.
+func (cm *ComponentManager[T]) GetField(e entity.Entity, path string) (any, error) {
+	component, f, err := cm.field(e, path)
+	if err != nil {
+		return nil, err
+	}
+	return f.Get(component), nil
+}
+
+// SetField writes a field of the entity's component, addressed like
+// GetField.
+func (cm *ComponentManager[T]) SetField(e entity.Entity, path string, value any) error {
+	component, f, err := cm.field(e, path)
+	if err != nil {
+		return err
+	}
+	if f.Set == nil {
+		return fmt.Errorf("field %q is read-only", path)
+	}
+	if err := f.Set(component, value); err != nil {
+		return fmt.Errorf("field %q: %w", path, err)
+	}
+	return nil
+}
+
+func (cm *ComponentManager[T]) field(e entity.Entity, path string) (*T, Field[T], error) {
+	f, ok := cm.fields[strings.ToLower(path)]
+	if !ok {
+		return nil, f, fmt.Errorf("no field %q in %T", path, *new(T))
+	}
+	component := cm.Get(e)
+	if component == nil {
+		return nil, f, fmt.Errorf("entity %d has no %T component", e, *new(T))
+	}
+	return component, f, nil
+}
@@ -0,0 +1,150 @@
+package components
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// ============================================
+// Field Access Tests
+// ============================================
+
+type unit struct {
+	Health Health
+	Level  uint8
+	Ratio  float32
+	Label  string
+	Boss   bool
+}
+
+// unitManager returns a manager for unit with a field table, holding one unit
+// for entity 1.
+func unitManager() (*ComponentManager[unit], entity.Entity) {
+	units := NewComponentManager[unit]()
+	units.DefineFields(
+		NumberField("health.current", func(u *unit) *int { return &u.Health.Current }),
+		NumberField("level", func(u *unit) *uint8 { return &u.Level }),
+		NumberField("ratio", func(u *unit) *float32 { return &u.Ratio }),
+		StringField("label", func(u *unit) *string { return &u.Label }),
+		BoolField("boss", func(u *unit) *bool { return &u.Boss }),
+		Field[unit]{Name: "health.max", Get: func(u *unit) any { return u.Health.Max }},
+	)
+	e := entity.NewEntity(1, 1)
+	units.Add(e, unit{Health: Health{Current: 7, Max: 10}, Level: 5, Label: "grunt"})
+	return units, e
+}
+
+func TestGetField(t *testing.T) {
+	units, e := unitManager()
+
+	cases := map[string]any{
+		"Health.Current": 7,
+		"health.max":     10,
+		"LEVEL":          uint8(5),
+		"label":          "grunt",
+		"boss":           false,
+	}
+	for path, want := range cases {
+		got, err := units.GetField(e, path)
+		if err != nil || got != want {
+			t.Errorf("GetField(%q) = %v, %v; want %v", path, got, err, want)
+		}
+	}
+}
+
+func TestGetFieldErrors(t *testing.T) {
+	units, e := unitManager()
+
+	for _, path := range []string{"armor", "health", ""} {
+		if _, err := units.GetField(e, path); err == nil {
+			t.Errorf("GetField(%q) should fail", path)
+		}
+	}
+	if _, err := units.GetField(entity.NewEntity(2, 1), "level"); err == nil {
+		t.Error("GetField on an entity without the component should fail")
+	}
+}
+
+func TestSetField(t *testing.T) {
+	units, e := unitManager()
+
+	writes := []struct {
+		path  string
+		value any
+	}{
+		{"health.current", 4.0},
+		{"level", 200},
+		{"ratio", 0.1},
+		{"label", "boss"},
+		{"boss", true},
+	}
+	for _, w := range writes {
+		if err := units.SetField(e, w.path, w.value); err != nil {
+			t.Errorf("SetField(%q, %v) failed: %v", w.path, w.value, err)
+		}
+	}
+
+	want := unit{Health: Health{Current: 4, Max: 10}, Level: 200, Ratio: 0.1, Label: "boss", Boss: true}
+	if got := *units.Get(e); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestSetFieldErrors(t *testing.T) {
+	units, e := unitManager()
+
+	cases := []struct {
+		path  string
+		value any
+	}{
+		{"level", 2.5},
+		{"level", 300},
+		{"level", -1},
+		{"level", "high"},
+		{"label", 1},
+		{"boss", nil},
+		{"health.max", 1}, // read-only
+		{"missing", 1},
+	}
+	for _, c := range cases {
+		if err := units.SetField(e, c.path, c.value); err == nil {
+			t.Errorf("SetField(%q, %v) should fail", c.path, c.value)
+		}
+	}
+	if units.Get(e).Level != 5 {
+		t.Errorf("Failed writes should leave the field alone, got %d", units.Get(e).Level)
+	}
+	if err := units.SetField(entity.NewEntity(2, 1), "level", 1); err == nil {
+		t.Error("SetField on an entity without the component should fail")
+	}
+}
+
+func TestDefineFieldsTwicePanics(t *testing.T) {
+	units, _ := unitManager()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Defining a field twice should panic")
+		}
+	}()
+	units.DefineFields(NumberField("Level", func(u *unit) *uint8 { return &u.Level }))
+}
+
+func TestBuiltinFields(t *testing.T) {
+	world := entity.NewWorld()
+	RegisterBuiltins(world)
+
+	want := map[string][]string{
+		"position": {"x", "y"},
+		"velocity": {"x", "y"},
+		"display":  {"b", "g", "height", "r", "width"},
+	}
+	for name, fields := range want {
+		store, ok := world.Component(name).(interface{ FieldNames() []string })
+		if !ok || !slices.Equal(store.FieldNames(), fields) {
+			t.Errorf("%s: expected fields %v", name, fields)
+		}
+	}
+}
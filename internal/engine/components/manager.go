@@ -1,6 +1,9 @@
 package components
 
 import (
+	"slices"
+	"sort"
+
 	"github.com/GiannisPettas/ember2D/internal/engine/entity"
 )
 
@@ -13,45 +16,330 @@ import (
 //	positions.Add(playerID, Position{X: 100, Y: 50})
 //	pos := positions.Get(playerID)  // returns *Position, no type assertion!
 type ComponentManager[T any] struct {
-	data map[entity.Entity]*T
+	store    storage[T]
+	onRemove []func(entity.Entity, *T)
+	fields   map[string]Field[T] // lower-cased name -> accessor, see DefineFields
+}
+
+// storage is the backend a ComponentManager keeps its data in.
+type storage[T any] interface {
+	add(e entity.Entity, component T)
+	get(e entity.Entity) *T
+	remove(e entity.Entity)
+	stale(e entity.Entity) (entity.Entity, bool)
+	each(fn func(entity.Entity, *T))
+	count() int
 }
 
 // NewComponentManager creates a new ComponentManager for type T.
+// Components are allocated individually, so pointers returned by Get stay
+// valid for as long as the component exists.
 func NewComponentManager[T any]() *ComponentManager[T] {
 	return &ComponentManager[T]{
-		data: make(map[entity.Entity]*T),
+		store: &mapStorage[T]{data: make(map[entity.Entity]*T)},
+	}
+}
+
+// NewDenseComponentManager creates a ComponentManager for type T backed by a
+// sparse set: components live contiguously in a dense array, which makes Each
+// much faster for large entity counts.
+//
+// Pointers returned by Get or passed to Each are only valid until the next
+// Add, or the next Remove outside of Each, on this manager. Components added
+// during Each are not visited by it.
+func NewDenseComponentManager[T any]() *ComponentManager[T] {
+	return &ComponentManager[T]{
+		store: &sparseSet[T]{},
 	}
 }
 
 // Add attaches a component to an entity. Overwrites if already exists.
+// A component still held by an older generation of e's slot is removed
+// first, running OnRemove hooks; if a newer generation holds the slot, e is
+// a dead handle and Add does nothing.
 func (cm *ComponentManager[T]) Add(e entity.Entity, component T) {
-	cm.data[e] = &component
+	if other, ok := cm.store.stale(e); ok {
+		if newer(other, e) {
+			return
+		}
+		cm.Remove(other)
+	}
+	cm.store.add(e, component)
+}
+
+// newer reports whether a is a later generation of b's slot. The difference
+// is taken as signed so generation counters that wrapped still compare right.
+func newer(a, b entity.Entity) bool {
+	return int32(a.Generation()-b.Generation()) > 0
 }
 
 // Get retrieves the component for an entity. Returns nil if not found.
 func (cm *ComponentManager[T]) Get(e entity.Entity) *T {
-	return cm.data[e]
+	return cm.store.get(e)
 }
 
-// Remove detaches a component from an entity.
+// Remove detaches a component from an entity, running OnRemove hooks first.
 func (cm *ComponentManager[T]) Remove(e entity.Entity) {
-	delete(cm.data, e)
+	component := cm.store.get(e)
+	if component == nil {
+		return
+	}
+	for _, fn := range cm.onRemove {
+		fn(e, component)
+	}
+	cm.store.remove(e)
+}
+
+// OnRemove registers a hook called before a component is removed, either
+// explicitly or by World.Cleanup. Use it to release resources held by T.
+func (cm *ComponentManager[T]) OnRemove(fn func(entity.Entity, *T)) {
+	cm.onRemove = append(cm.onRemove, fn)
 }
 
 // Has checks if an entity has this component.
 func (cm *ComponentManager[T]) Has(e entity.Entity) bool {
-	_, exists := cm.data[e]
-	return exists
+	return cm.store.get(e) != nil
 }
 
-// Each iterates over all entities with this component.
+// Each iterates over all entities with this component in ascending entity ID
+// order, so results are identical across runs.
 func (cm *ComponentManager[T]) Each(fn func(entity.Entity, *T)) {
-	for e, component := range cm.data {
-		fn(e, component)
-	}
+	cm.store.each(fn)
+}
+
+// eachEntity visits every entity with this component; used by queries.
+func (cm *ComponentManager[T]) eachEntity(fn func(entity.Entity)) {
+	cm.store.each(func(e entity.Entity, _ *T) {
+		fn(e)
+	})
 }
 
 // Count returns how many entities have this component.
 func (cm *ComponentManager[T]) Count() int {
-	return len(cm.data)
+	return cm.store.count()
+}
+
+// ============================================
+// Map storage
+// ============================================
+
+// mapStorage keeps one heap allocation per component, keyed by entity.
+// keys caches the sorted entity list used for deterministic iteration; it is
+// rebuilt lazily after removals or out-of-order additions.
+type mapStorage[T any] struct {
+	data   map[entity.Entity]*T
+	keys   []entity.Entity
+	sorted bool
+}
+
+func (s *mapStorage[T]) add(e entity.Entity, component T) {
+	if _, exists := s.data[e]; !exists && s.sorted {
+		if n := len(s.keys); n == 0 || s.keys[n-1] < e {
+			s.keys = append(s.keys, e)
+		} else {
+			s.sorted = false
+		}
+	}
+	s.data[e] = &component
+}
+
+func (s *mapStorage[T]) get(e entity.Entity) *T {
+	return s.data[e]
+}
+
+func (s *mapStorage[T]) remove(e entity.Entity) {
+	delete(s.data, e)
+	s.sorted = false
+}
+
+// stale never finds anything: each generation has its own key, and World
+// cleanup removes the old one.
+func (s *mapStorage[T]) stale(e entity.Entity) (entity.Entity, bool) {
+	return entity.Null, false
+}
+
+func (s *mapStorage[T]) each(fn func(entity.Entity, *T)) {
+	if !s.sorted {
+		// Build a new slice: an outer Each may still be ranging over the old
+		// one when a nested Each re-sorts.
+		keys := make([]entity.Entity, 0, len(s.data))
+		for e := range s.data {
+			keys = append(keys, e)
+		}
+		slices.Sort(keys)
+		s.keys = keys
+		s.sorted = true
+	}
+	// fn may remove components, so look each one up again.
+	for _, e := range s.keys {
+		if component := s.data[e]; component != nil {
+			fn(e, component)
+		}
+	}
+}
+
+func (s *mapStorage[T]) count() int {
+	return len(s.data)
+}
+
+// ============================================
+// Sparse set storage
+// ============================================
+
+// sparseSet packs components into a dense array. The sparse slice is indexed
+// by entity slot index and holds position+1 in dense (0 means absent), so
+// growing it never needs a fill loop.
+//
+// Removal swaps the last element into the hole, so the dense arrays are
+// re-sorted by entity ID before the next iteration when needed. During Each
+// nothing may move, so removal only clears the sparse entry and leaves a hole
+// that is compacted once the outermost Each returns.
+type sparseSet[T any] struct {
+	sparse    []int32
+	dense     []T
+	entities  []entity.Entity // parallel to dense
+	unsorted  bool
+	iterating int // depth of nested each calls
+	holes     int // entries removed during each, not yet compacted
+}
+
+// position returns where e lives in dense, or -1.
+func (s *sparseSet[T]) position(e entity.Entity) int {
+	index := int(e.Index())
+	if index >= len(s.sparse) {
+		return -1
+	}
+	p := int(s.sparse[index]) - 1
+	if p < 0 || s.entities[p] != e {
+		return -1
+	}
+	return p
+}
+
+func (s *sparseSet[T]) add(e entity.Entity, component T) {
+	if p := s.position(e); p >= 0 {
+		s.dense[p] = component
+		return
+	}
+
+	// The manager has evicted any stale generation of this slot (see stale),
+	// so sparse[index] is free.
+	index := int(e.Index())
+	if index >= len(s.sparse) {
+		s.sparse = append(s.sparse, make([]int32, index+1-len(s.sparse))...)
+	}
+
+	if n := len(s.entities); n > 0 && s.entities[n-1] > e {
+		s.unsorted = true
+	}
+	s.dense = append(s.dense, component)
+	s.entities = append(s.entities, e)
+	s.sparse[index] = int32(len(s.dense))
+}
+
+func (s *sparseSet[T]) get(e entity.Entity) *T {
+	p := s.position(e)
+	if p < 0 {
+		return nil
+	}
+	return &s.dense[p]
+}
+
+// stale returns the entity of another generation holding e's slot, which
+// may be older or newer than e.
+func (s *sparseSet[T]) stale(e entity.Entity) (entity.Entity, bool) {
+	index := int(e.Index())
+	if index >= len(s.sparse) || s.sparse[index] == 0 {
+		return entity.Null, false
+	}
+	old := s.entities[s.sparse[index]-1]
+	return old, old != e
+}
+
+// remove swaps the last element into the freed position, or leaves a hole
+// while each is running.
+func (s *sparseSet[T]) remove(e entity.Entity) {
+	p := s.position(e)
+	if p < 0 {
+		return
+	}
+	if s.iterating > 0 {
+		var zero T
+		s.dense[p] = zero
+		s.sparse[e.Index()] = 0
+		s.holes++
+		return
+	}
+	last := len(s.dense) - 1
+	if p != last {
+		moved := s.entities[last]
+		s.dense[p] = s.dense[last]
+		s.entities[p] = moved
+		s.sparse[moved.Index()] = int32(p + 1)
+		s.unsorted = true
+	}
+
+	var zero T
+	s.dense[last] = zero // release references held by the removed component
+	s.dense = s.dense[:last]
+	s.entities = s.entities[:last]
+	s.sparse[e.Index()] = 0
+}
+
+func (s *sparseSet[T]) each(fn func(entity.Entity, *T)) {
+	if s.unsorted && s.iterating == 0 {
+		sort.Sort(s)
+		for i, e := range s.entities {
+			s.sparse[e.Index()] = int32(i + 1)
+		}
+		s.unsorted = false
+	}
+
+	s.iterating++
+	defer s.endEach()
+	for i, n := 0, len(s.dense); i < n; i++ {
+		if s.live(i) {
+			fn(s.entities[i], &s.dense[i])
+		}
+	}
+}
+
+// live reports whether position i holds a component rather than a hole.
+func (s *sparseSet[T]) live(i int) bool {
+	return s.sparse[s.entities[i].Index()] == int32(i+1)
+}
+
+// endEach compacts the holes left by removals once no each is running,
+// keeping the remaining entries in order.
+func (s *sparseSet[T]) endEach() {
+	s.iterating--
+	if s.iterating > 0 || s.holes == 0 {
+		return
+	}
+	n := 0
+	for i, e := range s.entities {
+		if !s.live(i) {
+			continue
+		}
+		s.dense[n] = s.dense[i]
+		s.entities[n] = e
+		s.sparse[e.Index()] = int32(n + 1)
+		n++
+	}
+	clear(s.dense[n:])
+	s.dense = s.dense[:n]
+	s.entities = s.entities[:n]
+	s.holes = 0
+}
+
+func (s *sparseSet[T]) count() int {
+	return len(s.dense) - s.holes
+}
+
+// sort.Interface over the parallel dense arrays, ordered by entity ID.
+func (s *sparseSet[T]) Len() int           { return len(s.entities) }
+func (s *sparseSet[T]) Less(i, j int) bool { return s.entities[i] < s.entities[j] }
+func (s *sparseSet[T]) Swap(i, j int) {
+	s.entities[i], s.entities[j] = s.entities[j], s.entities[i]
+	s.dense[i], s.dense[j] = s.dense[j], s.dense[i]
 }
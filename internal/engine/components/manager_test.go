@@ -1,6 +1,7 @@
 package components
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/GiannisPettas/ember2D/internal/engine/entity"
@@ -246,3 +247,386 @@ func TestMultipleManagers(t *testing.T) {
 		t.Error("Should still have health")
 	}
 }
+
+// ============================================
+// Dense Storage Tests
+// ============================================
+
+func TestDenseAddGetRemove(t *testing.T) {
+	positions := NewDenseComponentManager[Position]()
+
+	positions.Add(entity.Entity(0), Position{X: 1})
+	positions.Add(entity.Entity(1), Position{X: 2})
+	positions.Add(entity.Entity(2), Position{X: 3})
+	positions.Add(entity.Entity(1), Position{X: 22}) // overwrite
+
+	if positions.Count() != 3 {
+		t.Errorf("Expected count 3, got %d", positions.Count())
+	}
+	if pos := positions.Get(entity.Entity(1)); pos == nil || pos.X != 22 {
+		t.Errorf("Expected X=22 after overwrite, got %v", pos)
+	}
+
+	positions.Remove(entity.Entity(0))
+
+	if positions.Has(entity.Entity(0)) {
+		t.Error("Has should return false after Remove")
+	}
+	// The last element was swapped into the hole; lookups must still work
+	if pos := positions.Get(entity.Entity(2)); pos == nil || pos.X != 3 {
+		t.Errorf("Expected X=3 for swapped entity, got %v", pos)
+	}
+	if positions.Count() != 2 {
+		t.Errorf("Expected count 2, got %d", positions.Count())
+	}
+}
+
+func TestDenseGetNonExistent(t *testing.T) {
+	positions := NewDenseComponentManager[Position]()
+	positions.Add(entity.Entity(3), Position{})
+
+	if positions.Get(entity.Entity(1)) != nil {
+		t.Error("Get should return nil for an index inside the sparse range")
+	}
+	if positions.Get(entity.Entity(1000)) != nil {
+		t.Error("Get should return nil for an index beyond the sparse range")
+	}
+
+	// Should not panic
+	positions.Remove(entity.Entity(1000))
+}
+
+func TestDenseRejectsStaleGeneration(t *testing.T) {
+	positions := NewDenseComponentManager[Position]()
+	old := entity.NewEntity(4, 1)
+	current := entity.NewEntity(4, 2)
+
+	positions.Add(old, Position{X: 1})
+
+	if positions.Has(current) {
+		t.Error("A newer generation should not see the old component")
+	}
+
+	positions.Add(current, Position{X: 2})
+
+	if positions.Has(old) {
+		t.Error("Adding a newer generation should replace the stale component")
+	}
+	if positions.Count() != 1 {
+		t.Errorf("Expected count 1, got %d", positions.Count())
+	}
+}
+
+func TestDenseEachOrderIsDeterministic(t *testing.T) {
+	positions := NewDenseComponentManager[Position]()
+	for i := 0; i < 5; i++ {
+		positions.Add(entity.Entity(i), Position{X: float64(i)})
+	}
+	positions.Remove(entity.Entity(1))
+
+	var got []entity.Entity
+	positions.Each(func(e entity.Entity, pos *Position) {
+		got = append(got, e)
+	})
+
+	want := []entity.Entity{0, 2, 3, 4}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestDenseOnRemove(t *testing.T) {
+	healths := NewDenseComponentManager[Health]()
+	healths.Add(entity.Entity(0), Health{Current: 7})
+
+	calls := 0
+	healths.OnRemove(func(e entity.Entity, hp *Health) {
+		calls++
+		if hp.Current != 7 {
+			t.Errorf("Expected Current=7 in hook, got %d", hp.Current)
+		}
+	})
+	healths.Remove(entity.Entity(0))
+
+	if calls != 1 {
+		t.Errorf("Expected hook to run once, ran %d times", calls)
+	}
+}
+
+func TestDenseStaleGenerationRunsOnRemove(t *testing.T) {
+	healths := NewDenseComponentManager[Health]()
+	old := entity.NewEntity(4, 1)
+	healths.Add(old, Health{Current: 3})
+
+	var removed []entity.Entity
+	healths.OnRemove(func(e entity.Entity, hp *Health) {
+		removed = append(removed, e)
+		if hp.Current != 3 {
+			t.Errorf("Expected the stale component in hook, got %+v", *hp)
+		}
+	})
+	healths.Add(entity.NewEntity(4, 2), Health{Current: 9})
+
+	if !slices.Equal(removed, []entity.Entity{old}) {
+		t.Errorf("Evicting a stale generation should run OnRemove, got %v", removed)
+	}
+}
+
+func TestDenseIgnoresOlderHandle(t *testing.T) {
+	healths := NewDenseComponentManager[Health]()
+	dead := entity.NewEntity(4, 1)
+	live := entity.NewEntity(4, 2)
+	healths.Add(live, Health{Current: 9})
+
+	removed := 0
+	healths.OnRemove(func(entity.Entity, *Health) { removed++ })
+	healths.Add(dead, Health{Current: 3})
+
+	if hp := healths.Get(live); hp == nil || hp.Current != 9 {
+		t.Errorf("Adding through a dead handle should not touch the live entity, got %v", hp)
+	}
+	if healths.Has(dead) || healths.Count() != 1 {
+		t.Error("Adding through a dead handle should not store anything")
+	}
+	if removed != 0 {
+		t.Errorf("Expected no OnRemove calls, got %d", removed)
+	}
+}
+
+// ============================================
+// Determinism Tests
+// ============================================
+
+// churnOrder adds and removes components in a fixed pattern and returns the
+// order Each visits them in.
+func churnOrder(cm *ComponentManager[Position]) []entity.Entity {
+	for i := 99; i >= 0; i-- {
+		cm.Add(entity.NewEntity(uint32(i), 1), Position{X: float64(i)})
+	}
+	for i := 0; i < 100; i += 3 {
+		cm.Remove(entity.NewEntity(uint32(i), 1))
+	}
+	cm.Add(entity.NewEntity(3, 2), Position{})
+
+	var order []entity.Entity
+	cm.Each(func(e entity.Entity, pos *Position) {
+		order = append(order, e)
+	})
+	return order
+}
+
+func TestEachOrderIsRepeatable(t *testing.T) {
+	for name, newManager := range backends {
+		first := churnOrder(newManager())
+		for i := 1; i < 20; i++ {
+			again := churnOrder(newManager())
+			if !slices.Equal(first, again) {
+				t.Fatalf("%s: run %d visited %v, first run visited %v", name, i, again, first)
+			}
+		}
+		if !slices.IsSorted(first) {
+			t.Errorf("%s: Each should visit entities in ascending ID order, got %v", name, first)
+		}
+	}
+}
+
+var backends = map[string]func() *ComponentManager[Position]{
+	"map":   NewComponentManager[Position],
+	"dense": NewDenseComponentManager[Position],
+}
+
+func TestEachOrderAfterRemoveDuringEach(t *testing.T) {
+	for name, newManager := range backends {
+		positions := newManager()
+		for i := 0; i < 10; i++ {
+			positions.Add(entity.Entity(i), Position{})
+		}
+
+		var visited []entity.Entity
+		positions.Each(func(e entity.Entity, pos *Position) {
+			visited = append(visited, e)
+			positions.Remove(e + 1)
+		})
+
+		want := []entity.Entity{0, 2, 4, 6, 8}
+		if !slices.Equal(visited, want) {
+			t.Errorf("%s: expected %v, got %v", name, want, visited)
+		}
+		if positions.Count() != 5 {
+			t.Errorf("%s: expected count 5, got %d", name, positions.Count())
+		}
+	}
+}
+
+func TestRemoveCurrentDuringEach(t *testing.T) {
+	for name, newManager := range backends {
+		positions := newManager()
+		for i := 4; i >= 0; i-- {
+			positions.Add(entity.Entity(i), Position{X: float64(i)})
+		}
+
+		var visited []entity.Entity
+		positions.Each(func(e entity.Entity, pos *Position) {
+			visited = append(visited, e)
+			if pos.X != float64(e) {
+				t.Errorf("%s: entity %d got component %+v", name, e, *pos)
+			}
+			if e%2 == 1 {
+				positions.Remove(e)
+				positions.Add(e+10, Position{X: float64(e + 10)}) // not visited
+			}
+		})
+
+		if want := []entity.Entity{0, 1, 2, 3, 4}; !slices.Equal(visited, want) {
+			t.Errorf("%s: expected %v, got %v", name, want, visited)
+		}
+
+		var after []entity.Entity
+		positions.Each(func(e entity.Entity, pos *Position) {
+			after = append(after, e)
+			if pos.X != float64(e) {
+				t.Errorf("%s: entity %d got component %+v", name, e, *pos)
+			}
+		})
+		if want := []entity.Entity{0, 2, 4, 11, 13}; !slices.Equal(after, want) {
+			t.Errorf("%s: expected %v after Each, got %v", name, want, after)
+		}
+	}
+}
+
+func TestNestedEachAfterRemove(t *testing.T) {
+	for name, newManager := range backends {
+		positions := newManager()
+		for i := 0; i < 5; i++ {
+			positions.Add(entity.Entity(i), Position{})
+		}
+
+		var outer []entity.Entity
+		positions.Each(func(e entity.Entity, _ *Position) {
+			outer = append(outer, e)
+			if e == 0 {
+				positions.Remove(e)
+			}
+			positions.Each(func(entity.Entity, *Position) {})
+		})
+
+		want := []entity.Entity{0, 1, 2, 3, 4}
+		if !slices.Equal(outer, want) {
+			t.Errorf("%s: a nested Each should not disturb the outer one: expected %v, got %v", name, want, outer)
+		}
+	}
+}
+
+func TestQueryOrderMatchesEntityOrder(t *testing.T) {
+	positions := NewDenseComponentManager[Position]()
+	velocities := NewComponentManager[Velocity]()
+	for i := 9; i >= 0; i-- {
+		positions.Add(entity.Entity(i), Position{})
+		velocities.Add(entity.Entity(i), Velocity{})
+	}
+	positions.Remove(entity.Entity(4))
+
+	var visited []entity.Entity
+	NewQuery2(positions, velocities).Each(func(e entity.Entity, _ *Position, _ *Velocity) {
+		visited = append(visited, e)
+	})
+
+	if !slices.IsSorted(visited) || len(visited) != 9 {
+		t.Errorf("Expected 9 entities in ascending order, got %v", visited)
+	}
+}
+
+// ============================================
+// Benchmarks
+// ============================================
+
+const benchEntities = 50_000
+
+func fillPositions(cm *ComponentManager[Position]) {
+	for i := 0; i < benchEntities; i++ {
+		cm.Add(entity.NewEntity(uint32(i), 1), Position{X: float64(i)})
+	}
+}
+
+func BenchmarkEachMap(b *testing.B) {
+	positions := NewComponentManager[Position]()
+	fillPositions(positions)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		positions.Each(func(e entity.Entity, pos *Position) {
+			pos.X += 1
+		})
+	}
+}
+
+func BenchmarkEachDense(b *testing.B) {
+	positions := NewDenseComponentManager[Position]()
+	fillPositions(positions)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		positions.Each(func(e entity.Entity, pos *Position) {
+			pos.X += 1
+		})
+	}
+}
+
+func BenchmarkGetMap(b *testing.B) {
+	positions := NewComponentManager[Position]()
+	fillPositions(positions)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		positions.Get(entity.NewEntity(uint32(i%benchEntities), 1))
+	}
+}
+
+func BenchmarkGetDense(b *testing.B) {
+	positions := NewDenseComponentManager[Position]()
+	fillPositions(positions)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		positions.Get(entity.NewEntity(uint32(i%benchEntities), 1))
+	}
+}
+
+func BenchmarkQuery2Map(b *testing.B) {
+	positions := NewComponentManager[Position]()
+	velocities := NewComponentManager[Velocity]()
+	fillPositions(positions)
+	for i := 0; i < benchEntities; i += 2 {
+		velocities.Add(entity.NewEntity(uint32(i), 1), Velocity{X: 1})
+	}
+	q := NewQuery2(positions, velocities)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		q.Each(func(e entity.Entity, pos *Position, vel *Velocity) {
+			pos.X += vel.X
+		})
+	}
+}
+
+func BenchmarkQuery2Dense(b *testing.B) {
+	positions := NewDenseComponentManager[Position]()
+	velocities := NewDenseComponentManager[Velocity]()
+	fillPositions(positions)
+	for i := 0; i < benchEntities; i += 2 {
+		velocities.Add(entity.NewEntity(uint32(i), 1), Velocity{X: 1})
+	}
+	q := NewQuery2(positions, velocities)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		q.Each(func(e entity.Entity, pos *Position, vel *Velocity) {
+			pos.X += vel.X
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package components
+
+import (
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Filter is anything that can tell whether an entity has something.
+// Every ComponentManager satisfies it, so managers can be passed to Without.
+type Filter interface {
+	Has(e entity.Entity) bool
+}
+
+// source is a set of entities a query can be driven from.
+type source interface {
+	Count() int
+	eachEntity(fn func(entity.Entity))
+}
+
+// tagSource drives a query from the entities carrying a tag.
+type tagSource struct {
+	tags *entity.TagManager
+	tag  string
+}
+
+func (s tagSource) Count() int {
+	return s.tags.CountByTag(s.tag)
+}
+
+func (s tagSource) eachEntity(fn func(entity.Entity)) {
+	for _, e := range s.tags.GetEntitiesByTag(s.tag) {
+		fn(e)
+	}
+}
+
+// queryFilter holds the tag requirements and exclusions shared by all query arities.
+type queryFilter struct {
+	tags    *entity.TagManager
+	with    []string
+	without []Filter
+}
+
+// accepts reports whether e passes the tag and exclusion filters.
+func (f *queryFilter) accepts(e entity.Entity) bool {
+	for _, tag := range f.with {
+		if !f.tags.HasTag(e, tag) {
+			return false
+		}
+	}
+	for _, ex := range f.without {
+		if ex.Has(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// smallest picks the cheapest set to iterate: the smallest component
+// manager or required tag.
+func (f *queryFilter) smallest(sources ...source) source {
+	best := sources[0]
+	for _, s := range sources[1:] {
+		if s.Count() < best.Count() {
+			best = s
+		}
+	}
+	for _, tag := range f.with {
+		if s := (tagSource{f.tags, tag}); s.Count() < best.Count() {
+			best = s
+		}
+	}
+	return best
+}
+
+// Query2 iterates entities that have both an A and a B component.
+//
+// Usage:
+//
+//	movers := components.NewQuery2(positions, velocities)
+//	movers.Each(func(e entity.Entity, pos *Position, vel *Velocity) {
+//		pos.X += vel.X
+//	})
+type Query2[A, B any] struct {
+	a      *ComponentManager[A]
+	b      *ComponentManager[B]
+	filter queryFilter
+}
+
+// NewQuery2 creates a query over two component managers.
+func NewQuery2[A, B any](a *ComponentManager[A], b *ComponentManager[B]) *Query2[A, B] {
+	return &Query2[A, B]{a: a, b: b}
+}
+
+// WithTags restricts the query to entities that have all the given tags.
+func (q *Query2[A, B]) WithTags(tags *entity.TagManager, names ...string) *Query2[A, B] {
+	q.filter.tags = tags
+	q.filter.with = append(q.filter.with, names...)
+	return q
+}
+
+// Without excludes entities that have any of the given components.
+func (q *Query2[A, B]) Without(filters ...Filter) *Query2[A, B] {
+	q.filter.without = append(q.filter.without, filters...)
+	return q
+}
+
+// Each calls fn for every matching entity, driving the join from the smallest set.
+func (q *Query2[A, B]) Each(fn func(entity.Entity, *A, *B)) {
+	q.filter.smallest(q.a, q.b).eachEntity(func(e entity.Entity) {
+		a := q.a.Get(e)
+		if a == nil {
+			return
+		}
+		b := q.b.Get(e)
+		if b == nil {
+			return
+		}
+		if !q.filter.accepts(e) {
+			return
+		}
+		fn(e, a, b)
+	})
+}
+
+// Query3 iterates entities that have an A, a B and a C component.
+type Query3[A, B, C any] struct {
+	a      *ComponentManager[A]
+	b      *ComponentManager[B]
+	c      *ComponentManager[C]
+	filter queryFilter
+}
+
+// NewQuery3 creates a query over three component managers.
+func NewQuery3[A, B, C any](a *ComponentManager[A], b *ComponentManager[B], c *ComponentManager[C]) *Query3[A, B, C] {
+	return &Query3[A, B, C]{a: a, b: b, c: c}
+}
+
+// WithTags restricts the query to entities that have all the given tags.
+func (q *Query3[A, B, C]) WithTags(tags *entity.TagManager, names ...string) *Query3[A, B, C] {
+	q.filter.tags = tags
+	q.filter.with = append(q.filter.with, names...)
+	return q
+}
+
+// Without excludes entities that have any of the given components.
+func (q *Query3[A, B, C]) Without(filters ...Filter) *Query3[A, B, C] {
+	q.filter.without = append(q.filter.without, filters...)
+	return q
+}
+
+// Each calls fn for every matching entity, driving the join from the smallest set.
+func (q *Query3[A, B, C]) Each(fn func(entity.Entity, *A, *B, *C)) {
+	q.filter.smallest(q.a, q.b, q.c).eachEntity(func(e entity.Entity) {
+		a := q.a.Get(e)
+		if a == nil {
+			return
+		}
+		b := q.b.Get(e)
+		if b == nil {
+			return
+		}
+		c := q.c.Get(e)
+		if c == nil {
+			return
+		}
+		if !q.filter.accepts(e) {
+			return
+		}
+		fn(e, a, b, c)
+	})
+}
@@ -0,0 +1,140 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Test component types
+type Frozen struct{}
+
+// ============================================
+// Query2 Tests
+// ============================================
+
+func TestQuery2OnlyVisitsEntitiesWithBoth(t *testing.T) {
+	positions := NewComponentManager[Position]()
+	velocities := NewComponentManager[Velocity]()
+
+	positions.Add(entity.Entity(0), Position{X: 1})
+	positions.Add(entity.Entity(1), Position{X: 2})
+	velocities.Add(entity.Entity(1), Velocity{X: 10})
+	velocities.Add(entity.Entity(2), Velocity{X: 20})
+
+	visited := 0
+	NewQuery2(positions, velocities).Each(func(e entity.Entity, pos *Position, vel *Velocity) {
+		visited++
+		if e != entity.Entity(1) {
+			t.Errorf("Unexpected entity %d in query", e)
+		}
+		pos.X += vel.X
+	})
+
+	if visited != 1 {
+		t.Errorf("Expected 1 entity, visited %d", visited)
+	}
+	if positions.Get(entity.Entity(1)).X != 12 {
+		t.Errorf("Expected X=12 after query, got %f", positions.Get(entity.Entity(1)).X)
+	}
+}
+
+func TestQuery2WithTags(t *testing.T) {
+	world := entity.NewWorld()
+	positions := NewComponentManager[Position]()
+	velocities := NewComponentManager[Velocity]()
+
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	for _, e := range []entity.Entity{player, enemy} {
+		positions.Add(e, Position{})
+		velocities.Add(e, Velocity{})
+	}
+
+	var got []entity.Entity
+	NewQuery2(positions, velocities).WithTags(world.Tags(), "enemy").Each(func(e entity.Entity, _ *Position, _ *Velocity) {
+		got = append(got, e)
+	})
+
+	if len(got) != 1 || got[0] != enemy {
+		t.Errorf("Expected only the enemy, got %v", got)
+	}
+}
+
+func TestQuery2Without(t *testing.T) {
+	positions := NewComponentManager[Position]()
+	velocities := NewComponentManager[Velocity]()
+	frozen := NewComponentManager[Frozen]()
+
+	for i := 0; i < 3; i++ {
+		positions.Add(entity.Entity(i), Position{})
+		velocities.Add(entity.Entity(i), Velocity{})
+	}
+	frozen.Add(entity.Entity(1), Frozen{})
+
+	visited := 0
+	NewQuery2(positions, velocities).Without(frozen).Each(func(e entity.Entity, _ *Position, _ *Velocity) {
+		visited++
+		if e == entity.Entity(1) {
+			t.Error("Frozen entity should be excluded")
+		}
+	})
+
+	if visited != 2 {
+		t.Errorf("Expected 2 entities, visited %d", visited)
+	}
+}
+
+func TestQuery2DrivesFromTagWhenSmallest(t *testing.T) {
+	world := entity.NewWorld()
+	positions := NewComponentManager[Position]()
+	velocities := NewComponentManager[Velocity]()
+
+	for i := 0; i < 50; i++ {
+		e := world.CreateEntity()
+		positions.Add(e, Position{})
+		velocities.Add(e, Velocity{})
+	}
+	boss := world.CreateEntity("boss")
+	positions.Add(boss, Position{})
+	velocities.Add(boss, Velocity{})
+
+	q := NewQuery2(positions, velocities).WithTags(world.Tags(), "boss")
+	if _, ok := q.filter.smallest(q.a, q.b).(tagSource); !ok {
+		t.Error("Query should iterate the tag set when it is the smallest")
+	}
+
+	visited := 0
+	q.Each(func(e entity.Entity, _ *Position, _ *Velocity) { visited++ })
+	if visited != 1 {
+		t.Errorf("Expected 1 boss, visited %d", visited)
+	}
+}
+
+// ============================================
+// Query3 Tests
+// ============================================
+
+func TestQuery3(t *testing.T) {
+	positions := NewComponentManager[Position]()
+	velocities := NewComponentManager[Velocity]()
+	healths := NewComponentManager[Health]()
+
+	for i := 0; i < 4; i++ {
+		positions.Add(entity.Entity(i), Position{})
+		velocities.Add(entity.Entity(i), Velocity{})
+	}
+	healths.Add(entity.Entity(2), Health{Current: 5, Max: 10})
+
+	visited := 0
+	NewQuery3(positions, velocities, healths).Each(func(e entity.Entity, _ *Position, _ *Velocity, hp *Health) {
+		visited++
+		if hp.Current != 5 {
+			t.Errorf("Expected Current=5, got %d", hp.Current)
+		}
+	})
+
+	if visited != 1 {
+		t.Errorf("Expected 1 entity, visited %d", visited)
+	}
+}
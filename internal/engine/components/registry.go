@@ -0,0 +1,59 @@
+package components
+
+import (
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Register creates a ComponentManager for T and registers it with the world
+// under name, so its data is purged when entities are destroyed. The given
+// fields become readable and writable from rule files (see DefineFields).
+//
+// Usage:
+//
+//	positions := components.Register[Position](world, "position")
+//	healths := components.Register(world, "health",
+//		components.NumberField("current", func(h *Health) *int { return &h.Current }),
+//	)
+func Register[T any](w *entity.World, name string, fields ...Field[T]) *ComponentManager[T] {
+	cm := NewComponentManager[T]()
+	cm.DefineFields(fields...)
+	w.RegisterComponent(name, cm)
+	return cm
+}
+
+// RegisterDense is like Register but backs the manager with dense storage
+// (see NewDenseComponentManager).
+func RegisterDense[T any](w *entity.World, name string, fields ...Field[T]) *ComponentManager[T] {
+	cm := NewDenseComponentManager[T]()
+	cm.DefineFields(fields...)
+	w.RegisterComponent(name, cm)
+	return cm
+}
+
+// RegisterBuiltins registers the engine's built-in component types under
+// their standard scene names: "position", "velocity" and "display", with
+// their fields named as in scene files.
+func RegisterBuiltins(w *entity.World) {
+	Register(w, "position",
+		NumberField("x", func(p *Position) *float64 { return &p.X }),
+		NumberField("y", func(p *Position) *float64 { return &p.Y }),
+	)
+	Register(w, "velocity",
+		NumberField("x", func(v *Velocity) *float64 { return &v.X }),
+		NumberField("y", func(v *Velocity) *float64 { return &v.Y }),
+	)
+	Register(w, "display",
+		NumberField("width", func(d *Display) *float64 { return &d.Width }),
+		NumberField("height", func(d *Display) *float64 { return &d.Height }),
+		NumberField("r", func(d *Display) *uint8 { return &d.R }),
+		NumberField("g", func(d *Display) *uint8 { return &d.G }),
+		NumberField("b", func(d *Display) *uint8 { return &d.B }),
+	)
+}
+
+// Lookup returns the manager registered under name, or nil if there is none
+// or it stores a different component type.
+func Lookup[T any](w *entity.World, name string) *ComponentManager[T] {
+	cm, _ := w.Component(name).(*ComponentManager[T])
+	return cm
+}
@@ -0,0 +1,92 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// ============================================
+// Registry Tests
+// ============================================
+
+func TestRegisterAndLookup(t *testing.T) {
+	world := entity.NewWorld()
+	positions := Register[Position](world, "position")
+
+	if Lookup[Position](world, "Position") != positions {
+		t.Error("Lookup should return the registered manager")
+	}
+	if Lookup[Velocity](world, "position") != nil {
+		t.Error("Lookup with the wrong type should return nil")
+	}
+	if Lookup[Position](world, "missing") != nil {
+		t.Error("Lookup of an unknown name should return nil")
+	}
+}
+
+func TestCleanupRemovesComponents(t *testing.T) {
+	world := entity.NewWorld()
+	positions := Register[Position](world, "position")
+	healths := Register[Health](world, "health")
+
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	positions.Add(player, Position{})
+	positions.Add(enemy, Position{})
+	healths.Add(enemy, Health{Current: 10, Max: 10})
+
+	world.DestroyEntity(enemy)
+	world.Cleanup()
+
+	if positions.Has(enemy) || healths.Has(enemy) {
+		t.Error("Cleanup should remove every component of a destroyed entity")
+	}
+	if !positions.Has(player) {
+		t.Error("Cleanup should keep components of alive entities")
+	}
+}
+
+// ============================================
+// OnRemove Tests
+// ============================================
+
+func TestOnRemoveCalledOnRemove(t *testing.T) {
+	healths := NewComponentManager[Health]()
+	healths.Add(entity.Entity(1), Health{Current: 3, Max: 10})
+
+	var released []int
+	healths.OnRemove(func(e entity.Entity, hp *Health) {
+		released = append(released, hp.Current)
+	})
+
+	healths.Remove(entity.Entity(1))
+	healths.Remove(entity.Entity(1)) // already gone, no hook
+
+	if len(released) != 1 || released[0] != 3 {
+		t.Errorf("Expected hook to run once with Current=3, got %v", released)
+	}
+}
+
+func TestOnRemoveCalledOnCleanup(t *testing.T) {
+	world := entity.NewWorld()
+	healths := Register[Health](world, "health")
+
+	enemy := world.CreateEntity("enemy")
+	healths.Add(enemy, Health{Current: 1, Max: 1})
+
+	calls := 0
+	healths.OnRemove(func(e entity.Entity, hp *Health) {
+		calls++
+		if e != enemy {
+			t.Errorf("Hook called for %d, expected %d", e, enemy)
+		}
+	})
+
+	world.DestroyEntity(enemy)
+	world.Cleanup()
+
+	if calls != 1 {
+		t.Errorf("Expected OnRemove to run once during Cleanup, ran %d times", calls)
+	}
+}
@@ -2,10 +2,12 @@ package components
 
 // Position represents an entity's location in 2D space.
 type Position struct {
-	X, Y float64
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
 }
 
 // Velocity represents an entity's speed and direction.
 type Velocity struct {
-	X, Y float64
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
 }
@@ -0,0 +1,43 @@
+package conditions
+
+import (
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+)
+
+// All passes when every nested condition passes (true when empty).
+type All struct {
+	Conditions []behavior.Condition
+}
+
+func (c *All) Evaluate(ctx *core.Context) bool {
+	for _, cond := range c.Conditions {
+		if !cond.Evaluate(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// Any passes when at least one nested condition passes (false when empty).
+type Any struct {
+	Conditions []behavior.Condition
+}
+
+func (c *Any) Evaluate(ctx *core.Context) bool {
+	for _, cond := range c.Conditions {
+		if cond.Evaluate(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// Not inverts a condition.
+type Not struct {
+	Condition behavior.Condition
+}
+
+func (c *Not) Evaluate(ctx *core.Context) bool {
+	return !c.Condition.Evaluate(ctx)
+}
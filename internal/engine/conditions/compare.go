@@ -0,0 +1,146 @@
+package conditions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+)
+
+// Compare compares two values, each a literal or a path read through
+// core.Context.Resolve, e.g.
+//
+//	A.Health.Current > 0
+//	event.damage >= 10
+//	A.team.name == B.team.name
+//	event.kind != "fire"
+//
+// Numbers compare numerically whatever their Go type; strings compare with
+// all operators, booleans only with == and !=. A path that cannot be resolved
+// or mismatched types make the condition fail.
+type Compare struct {
+	Left  Operand
+	Op    string // ==, !=, <, <=, > or >=
+	Right Operand
+}
+
+// Operand is one side of a Compare: a path if Path is set, else Value.
+type Operand struct {
+	Path  string
+	Value any // float64, string or bool
+}
+
+// operators are tried longest first so ">=" is not read as ">".
+var operators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// ParseCompare parses "left op right".
+func ParseCompare(expr string) (*Compare, error) {
+	for i := 0; i < len(expr); i++ {
+		if expr[i] == '"' || expr[i] == '\'' {
+			end := strings.IndexByte(expr[i+1:], expr[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in %q", expr)
+			}
+			i += end + 1
+			continue
+		}
+		for _, op := range operators {
+			if !strings.HasPrefix(expr[i:], op) {
+				continue
+			}
+			left, err := parseOperand(expr[:i])
+			if err != nil {
+				return nil, fmt.Errorf("left side of %q: %w", expr, err)
+			}
+			right, err := parseOperand(expr[i+len(op):])
+			if err != nil {
+				return nil, fmt.Errorf("right side of %q: %w", expr, err)
+			}
+			return &Compare{Left: left, Op: op, Right: right}, nil
+		}
+	}
+	return nil, fmt.Errorf("no comparison operator in %q", expr)
+}
+
+func parseOperand(s string) (Operand, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return Operand{}, fmt.Errorf("missing value")
+	case s == "true" || s == "false":
+		return Operand{Value: s == "true"}, nil
+	case len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]:
+		return Operand{Value: s[1 : len(s)-1]}, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return Operand{Value: f}, nil
+	}
+	if !strings.Contains(s, ".") || strings.ContainsFunc(s, func(r rune) bool {
+		return !(r == '.' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) {
+		return Operand{}, fmt.Errorf("%q is not a number, string, bool or path", s)
+	}
+	return Operand{Path: s}, nil
+}
+
+func (c *Compare) Evaluate(ctx *core.Context) bool {
+	left, err := c.Left.value(ctx)
+	if err != nil {
+		return false
+	}
+	right, err := c.Right.value(ctx)
+	if err != nil {
+		return false
+	}
+	return compareValues(left, c.Op, right)
+}
+
+func (o Operand) value(ctx *core.Context) (any, error) {
+	if o.Path == "" {
+		return o.Value, nil
+	}
+	return ctx.Resolve(o.Path)
+}
+
+func compareValues(left any, op string, right any) bool {
+	if l, ok := core.ToFloat(left); ok {
+		r, ok := core.ToFloat(right)
+		return ok && ordered(l < r, l == r, op)
+	}
+	if l, ok := left.(string); ok {
+		r, ok := right.(string)
+		return ok && ordered(l < r, l == r, op)
+	}
+	if l, ok := left.(bool); ok {
+		r, ok := right.(bool)
+		switch {
+		case !ok:
+			return false
+		case op == "==":
+			return l == r
+		case op == "!=":
+			return l != r
+		}
+	}
+	return false
+}
+
+// ordered applies op given how the two sides compare.
+func ordered(less, equal bool, op string) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	case ">=":
+		return !less
+	}
+	return false
+}
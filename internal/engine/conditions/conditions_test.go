@@ -0,0 +1,116 @@
+package conditions
+
+import (
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/components"
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Test component type
+type Health struct{ Current, Max int }
+
+// fixed is a condition with a constant result.
+type fixed bool
+
+func (f fixed) Evaluate(ctx *core.Context) bool { return bool(f) }
+
+// hitContext returns a context for player (A) hit by enemy (B).
+func hitContext(payload map[string]any) *core.Context {
+	world := entity.NewWorld()
+	health := components.Register(world, "health",
+		components.NumberField("current", func(h *Health) *int { return &h.Current }),
+		components.NumberField("max", func(h *Health) *int { return &h.Max }),
+	)
+	components.RegisterBuiltins(world)
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	health.Add(player, Health{Current: 30, Max: 100})
+	health.Add(enemy, Health{Current: 0, Max: 50})
+	return core.NewContext(world, core.Event{Type: "hit", A: player, B: enemy, Payload: payload})
+}
+
+// ============================================
+// Combinator Tests
+// ============================================
+
+func TestCombinators(t *testing.T) {
+	ctx := hitContext(nil)
+	cases := []struct {
+		name string
+		cond behavior.Condition
+		want bool
+	}{
+		{"all true", &All{Conditions: []behavior.Condition{fixed(true), fixed(true)}}, true},
+		{"all with false", &All{Conditions: []behavior.Condition{fixed(true), fixed(false)}}, false},
+		{"all empty", &All{}, true},
+		{"any with true", &Any{Conditions: []behavior.Condition{fixed(false), fixed(true)}}, true},
+		{"any false", &Any{Conditions: []behavior.Condition{fixed(false)}}, false},
+		{"any empty", &Any{}, false},
+		{"not", &Not{Condition: fixed(false)}, true},
+		{"nested", &Not{Condition: &Any{Conditions: []behavior.Condition{fixed(false), &All{}}}}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.cond.Evaluate(ctx); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+// ============================================
+// Compare Tests
+// ============================================
+
+func TestCompare(t *testing.T) {
+	ctx := hitContext(map[string]any{"damage": 12.0, "kind": "fire", "crit": true, "hits": 3})
+	cases := map[string]bool{
+		"A.Health.Current > 0":             true,
+		"B.health.current > 0":             false,
+		"event.damage >= 10":               true,
+		"event.damage < 12":                false,
+		"event.hits == 3":                  true,
+		"A.Health.Current <= B.Health.Max": true,
+		"A.Health.Current != A.Health.Max": true,
+		`event.kind == "fire"`:             true,
+		`event.kind != 'ice'`:              true,
+		`event.kind > "a"`:                 true,
+		"event.crit == true":               true,
+		"event.crit != true":               false,
+		"event.crit > false":               false, // bools only support == and !=
+		"event.kind == 3":                  false, // mismatched types
+		"event.missing > 0":                false,
+		"A.armor.value > 0":                false,
+		"A.position.x == 0":                false, // player has no position
+		"event.damage>=12":                 true,
+	}
+	for expr, want := range cases {
+		c, err := ParseCompare(expr)
+		if err != nil {
+			t.Errorf("ParseCompare(%q) failed: %v", expr, err)
+			continue
+		}
+		if got := c.Evaluate(ctx); got != want {
+			t.Errorf("%q: expected %v, got %v", expr, want, got)
+		}
+	}
+}
+
+func TestParseCompareErrors(t *testing.T) {
+	for _, expr := range []string{"", "event.damage", "event.damage >", "event.a + 1 > 0", "> 3", "damage > 3", `event.kind == "fire`, "event.x = 1"} {
+		if _, err := ParseCompare(expr); err == nil {
+			t.Errorf("ParseCompare(%q) should fail", expr)
+		}
+	}
+}
+
+func TestParseCompareOperands(t *testing.T) {
+	c, err := ParseCompare(`event.kind == "a >= b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Op != "==" || c.Left.Path != "event.kind" || c.Right.Value != "a >= b" {
+		t.Errorf("Operators inside strings should be ignored, got %+v", c)
+	}
+}
@@ -0,0 +1,62 @@
+package conditions
+
+import (
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/registry"
+)
+
+// Register adds the built-in conditions to r.
+func Register(r *registry.Registry) {
+	r.RegisterCondition(registry.ConditionSpec{
+		Name:        "always_true",
+		Description: "Never blocks the behavior.",
+		New: func(p registry.Params) (behavior.Condition, error) {
+			return &AlwaysTrue{}, nil
+		},
+	})
+
+	r.RegisterCondition(registry.ConditionSpec{
+		Name:        "all",
+		Description: "Passes when every nested condition passes.",
+		Params: []registry.ParamSpec{
+			{Name: "conditions", Type: registry.Conditions, Required: true},
+		},
+		New: func(p registry.Params) (behavior.Condition, error) {
+			return &All{Conditions: p.Conditions("conditions")}, nil
+		},
+	})
+	r.RegisterCondition(registry.ConditionSpec{
+		Name:        "any",
+		Description: "Passes when at least one nested condition passes.",
+		Params: []registry.ParamSpec{
+			{Name: "conditions", Type: registry.Conditions, Required: true},
+		},
+		New: func(p registry.Params) (behavior.Condition, error) {
+			return &Any{Conditions: p.Conditions("conditions")}, nil
+		},
+	})
+	r.RegisterCondition(registry.ConditionSpec{
+		Name:        "not",
+		Description: "Passes when the nested condition fails.",
+		Params: []registry.ParamSpec{
+			{Name: "condition", Type: registry.Condition, Required: true},
+		},
+		New: func(p registry.Params) (behavior.Condition, error) {
+			return &Not{Condition: p.Condition("condition")}, nil
+		},
+	})
+	r.RegisterCondition(registry.ConditionSpec{
+		Name:        "compare",
+		Description: "Compares two values, e.g. \"A.Health.Current > 0\" or \"event.damage >= 10\".",
+		Params: []registry.ParamSpec{
+			{Name: "expr", Type: registry.String, Required: true, Description: "left op right, with op one of == != < <= > >=."},
+		},
+		New: func(p registry.Params) (behavior.Condition, error) {
+			c, err := ParseCompare(p.String("expr"))
+			if err != nil {
+				return nil, &registry.ParamError{Param: "expr", Err: err}
+			}
+			return c, nil
+		},
+	})
+}
@@ -1,14 +1,41 @@
 package core
 
 import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
 	"github.com/GiannisPettas/ember2D/internal/engine/entity"
 )
 
 // Context is passed to conditions and actions during behavior execution.
-// It provides access to the World and the current Event.
+// It provides access to the World, the current Event and the dispatcher
+// running the behavior.
+//
+// The fields below Event are set by the dispatcher and are zero for contexts
+// built with NewContext alone.
 type Context struct {
 	World *entity.World
 	Event Event
+
+	// Emitter queues follow-up events; actions call Emit. The dispatcher
+	// binds it to its own Emit.
+	Emitter func(Event)
+
+	// Rand is the dispatcher's random source. It is seeded deterministically
+	// (see behavior.Dispatcher.Seed), so a replay with the same inputs makes
+	// the same choices. Use it instead of the global math/rand functions.
+	Rand *rand.Rand
+
+	// Delta is the duration of the frame being processed.
+	Delta time.Duration
+
+	// Scratch is private storage of the running behavior, kept between its
+	// runs until the behavior is removed, e.g. for cooldowns or counters.
+	Scratch map[string]any
+
+	consumed bool
 }
 
 // NewContext creates a new Context.
@@ -18,3 +45,93 @@ func NewContext(world *entity.World, ev Event) *Context {
 		Event: ev,
 	}
 }
+
+// Consume stops the event from reaching behaviors with a lower priority than
+// the one running. The remaining actions of the current behavior still run.
+func (c *Context) Consume() {
+	c.consumed = true
+}
+
+// Consumed reports whether an action consumed the event.
+func (c *Context) Consumed() bool {
+	return c.consumed
+}
+
+// Emit queues ev through the Emitter. It fails when there is none.
+func (c *Context) Emit(ev Event) error {
+	if c.Emitter == nil {
+		return fmt.Errorf("emit %q: context has no emitter", ev.Type)
+	}
+	c.Emitter(ev)
+	return nil
+}
+
+// Component returns the component store registered in the World under name,
+// or nil if there is none. For typed access use components.Lookup:
+//
+//	healths := components.Lookup[Health](ctx.World, "health")
+//	if hp := healths.Get(ctx.Event.B); hp != nil {
+//		hp.Current -= 10
+//	}
+func (c *Context) Component(name string) entity.ComponentStore {
+	return c.World.Component(name)
+}
+
+// FieldReader is a component store whose fields can be read by name, such
+// as a components.ComponentManager with fields from DefineFields.
+type FieldReader interface {
+	GetField(e entity.Entity, path string) (any, error)
+}
+
+// FieldWriter is the writing side of FieldReader, used by the set_field and
+// add_to_field actions.
+type FieldWriter interface {
+	SetField(e entity.Entity, path string, value any) error
+}
+
+// Resolve reads a value by path, as used by rule-file conditions:
+//
+//	event.damage          Payload["damage"]
+//	A.Health.Current      field Current of participant A's "health" component
+//	B.position.x          likewise for participant B
+//
+// The root and component names are case-insensitive.
+func (c *Context) Resolve(path string) (any, error) {
+	root, rest, _ := strings.Cut(path, ".")
+	if rest == "" {
+		return nil, fmt.Errorf("resolve %q: expected root.field", path)
+	}
+
+	var e entity.Entity
+	switch strings.ToLower(root) {
+	case "event":
+		v, ok := c.Event.Payload[rest]
+		if !ok {
+			return nil, fmt.Errorf("resolve %q: %w", path, &PayloadError{Key: rest, Err: ErrMissing})
+		}
+		return v, nil
+	case "a":
+		e = c.Event.A
+	case "b":
+		e = c.Event.B
+	default:
+		return nil, fmt.Errorf("resolve %q: unknown root %q (want event, A or B)", path, root)
+	}
+
+	if e == entity.Null {
+		return nil, fmt.Errorf("resolve %q: event has no participant %s", path, strings.ToUpper(root))
+	}
+	name, field, _ := strings.Cut(rest, ".")
+	if field == "" {
+		return nil, fmt.Errorf("resolve %q: expected %s.component.field", path, root)
+	}
+	store, ok := c.Component(name).(FieldReader)
+	if !ok {
+		return nil, fmt.Errorf("resolve %q: unknown or unreadable component %q", path, name)
+	}
+	v, err := store.GetField(e, field)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %q: %w", path, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// healthStore is a minimal FieldReader holding one "current" value per entity.
+type healthStore map[entity.Entity]int
+
+func (s healthStore) Has(e entity.Entity) bool { _, ok := s[e]; return ok }
+func (s healthStore) Remove(e entity.Entity)   { delete(s, e) }
+func (s healthStore) Count() int               { return len(s) }
+
+func (s healthStore) GetField(e entity.Entity, path string) (any, error) {
+	v, ok := s[e]
+	if !ok || path != "current" {
+		return nil, fmt.Errorf("no %s for entity %d", path, e)
+	}
+	return v, nil
+}
+
+// ============================================
+// Resolve Tests
+// ============================================
+
+func TestResolve(t *testing.T) {
+	world := entity.NewWorld()
+	health := healthStore{}
+	world.RegisterComponent("health", health)
+	player := world.CreateEntity()
+	health[player] = 42
+
+	ctx := NewContext(world, Event{A: player, Payload: map[string]any{"damage": 12.0}})
+
+	if v, err := ctx.Resolve("A.Health.current"); err != nil || v != 42 {
+		t.Errorf("Resolve(A.Health.current) = %v, %v", v, err)
+	}
+	if v, err := ctx.Resolve("event.damage"); err != nil || v != 12.0 {
+		t.Errorf("Resolve(event.damage) = %v, %v", v, err)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	world := entity.NewWorld()
+	world.RegisterComponent("health", healthStore{})
+	ctx := NewContext(world, Event{A: world.CreateEntity()})
+
+	for _, path := range []string{
+		"damage",           // no root
+		"C.health.current", // unknown root
+		"B.health.current", // no participant B
+		"A.health",         // no field
+		"A.armor.value",    // unknown component
+		"A.health.current", // entity lacks the component
+		"event.missing",    // missing payload key
+	} {
+		if _, err := ctx.Resolve(path); err == nil {
+			t.Errorf("Resolve(%q) should fail", path)
+		}
+	}
+}
+
+// ============================================
+// Context Access Tests
+// ============================================
+
+func TestContextComponent(t *testing.T) {
+	world := entity.NewWorld()
+	health := healthStore{}
+	world.RegisterComponent("health", health)
+	ctx := NewContext(world, Event{})
+
+	if store, ok := ctx.Component("Health").(healthStore); !ok || store == nil {
+		t.Errorf("Expected the registered health store, got %v", ctx.Component("Health"))
+	}
+	if ctx.Component("mana") != nil {
+		t.Error("Unknown component should return nil")
+	}
+}
+
+func TestContextEmitWithoutEmitter(t *testing.T) {
+	ctx := NewContext(entity.NewWorld(), Event{})
+	if err := ctx.Emit(Event{Type: "hit"}); err == nil {
+		t.Error("Emit without an emitter should fail")
+	}
+
+	var got []Event
+	ctx.Emitter = func(ev Event) { got = append(got, ev) }
+	if err := ctx.Emit(Event{Type: "hit"}); err != nil || len(got) != 1 {
+		t.Errorf("Emit should call the emitter, got %v, %v", got, err)
+	}
+}
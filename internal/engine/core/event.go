@@ -1,9 +1,29 @@
 package core
 
+import "github.com/GiannisPettas/ember2D/internal/engine/entity"
+
 // EventType is a human-readable string representing the "kind" of event.
 // Using strings (not iota ints) makes debugging, logging, and JSON import/export much easier.
 type EventType string
 
+// Event types emitted by the engine itself.
+const (
+	// EventStart fires once when the dispatcher starts, after the scene is loaded.
+	EventStart EventType = "start"
+	// EventTick fires on every dispatcher update (Payload["dt"] in seconds, Payload["frame"]).
+	EventTick EventType = "tick"
+	// EventEntityCreated fires when an entity is created (A=entity).
+	EventEntityCreated EventType = "entity_created"
+	// EventEntityDestroyed fires when an entity is destroyed (A=entity).
+	EventEntityDestroyed EventType = "entity_destroyed"
+	// EventTagAdded fires when an entity gains a tag (A=entity, Payload["tag"]).
+	EventTagAdded EventType = "tag_added"
+	// EventTagRemoved fires when an entity loses a tag (A=entity, Payload["tag"]).
+	EventTagRemoved EventType = "tag_removed"
+	// EventTimer fires when a timer trigger comes due (Payload["behavior"], Payload["count"]).
+	EventTimer EventType = "timer"
+)
+
 // Event is the fundamental "message" structure in ember2D.
 // Most game logic happens as a reaction to events.
 // This is designed to be simple but extensible.
@@ -14,10 +34,24 @@ type Event struct {
 
 	// A and B represent the main entities involved in the event.
 	// Most game events (e.g., collisions, interactions) concern 1 or 2 entities.
-	// If only one entity is relevant, B can be left blank ("").
-	// Example: player collides with enemy (A="player", B="enemy").
-	A string
-	B string
+	// If only one entity is relevant, B is left as entity.Null.
+	// Example: player collides with enemy (A=player, B=enemy).
+	A entity.Entity
+	B entity.Entity
+
+	// RoleA and RoleB optionally name the part each participant plays,
+	// e.g. "attacker" and "target". Triggers can filter on them.
+	RoleA string
+	RoleB string
+
+	// Loop counts how many times a loop behavior has already run for this
+	// event instance (0 on the first iteration).
+	Loop int
+
+	// Depth is how many events led to this one within the current frame when
+	// the dispatcher runs in cascade mode: 0 for events emitted from outside
+	// event processing, parent's Depth+1 for events emitted by actions.
+	Depth int
 
 	// Payload is a flexible map for extra data:
 	// - Custom fields (e.g. "damage": 20, "direction": "left")
@@ -33,27 +67,27 @@ type Event struct {
 //
 // 1. Simple collision between player and enemy:
 //    Event{
-//        Type: "collision",
-//        A:    "player",
-//        B:    "enemy1",
+//        Type:  "collision",
+//        A:     player,
+//        B:     enemy1,
+//        RoleA: "attacker",
+//        RoleB: "target",
 //        Payload: map[string]any{"damage": 15},
 //    }
 //
 // 2. Timer event with only one entity:
 //    Event{
 //        Type: "timer",
-//        A:    "spawner1",
-//        B:    "",
+//        A:    spawner1,
 //        Payload: map[string]any{"interval": 2.0},
 //    }
 //
 // 3. Area-of-effect event affecting multiple entities:
 //    Event{
 //        Type: "explosion",
-//        A:    "bomb1",
-//        B:    "",
+//        A:    bomb1,
 //        Payload: map[string]any{
-//            "affected": []string{"enemy1", "enemy2", "crate1"},
+//            "affected": []entity.Entity{enemy1, enemy2, crate1},
 //            "radius":   50,
 //        },
 //    }
@@ -61,30 +95,26 @@ type Event struct {
 // 4. Custom event from the editor:
 //    Event{
 //        Type: "powerup_collected",
-//        A:    "player",
-//        B:    "",
+//        A:    player,
 //        Payload: map[string]any{"powerup_type": "speed_boost"},
 //    }
 //---------------------------------------------------------------------------------------------
 // Example: Accessing an entity attribute from an event
 //
-// Suppose your World has a method: GetEntityByID(id string) *Entity
+// Events carry entity handles, not component data. Look the component up
+// through the Context when the action runs:
 //
-// func (w *World) GetEntityByID(id string) *Entity {
-//     return w.Entities[id]
-// }
-//
-// Suppose your Entity has a Components map (or fields).
-//
-// // Inside an Action or Condition:
-// func SlowDownPlayer(ctx *core.Context) {
-//     player := ctx.World.GetEntityByID(ctx.Event.A)
-//     if player != nil {
-//         // Assume the speed is stored as a float64 in Components
-//         if speed, ok := player.Components["speed"].(float64); ok {
-//             player.Components["speed"] = speed * 0.8
-//         }
+// // Inside an Action:
+// func (a *SlowDownPlayer) Execute(ctx *core.Context) error {
+//     velocities := components.Lookup[components.Velocity](ctx.World, "velocity")
+//     if velocities == nil {
+//         return errors.New("no velocity component registered")
+//     }
+//     if v := velocities.Get(ctx.Event.A); v != nil {
+//         v.X *= 0.8
+//         v.Y *= 0.8
 //     }
+//     return nil
 // }
 //
 // This ensures you always get the *current* value for the entity,
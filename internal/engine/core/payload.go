@@ -0,0 +1,229 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Vec2 is a 2D vector payload value, e.g. a position or a knockback direction.
+type Vec2 struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// ErrMissing is wrapped by PayloadError when a key is absent or nil.
+var ErrMissing = errors.New("missing")
+
+// PayloadError reports a payload value that is missing or has the wrong type.
+type PayloadError struct {
+	Key string
+	Err error
+}
+
+func (e *PayloadError) Error() string {
+	return fmt.Sprintf("payload %q: %v", e.Key, e.Err)
+}
+
+func (e *PayloadError) Unwrap() error {
+	return e.Err
+}
+
+// The getters below read typed values from the payload. Numbers are coerced
+// from any Go numeric type, since events built from JSON carry float64 where
+// Go code would use int.
+
+// GetFloat returns a number.
+func (e Event) GetFloat(key string) (float64, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	f, ok := ToFloat(v)
+	if !ok {
+		return 0, &PayloadError{Key: key, Err: fmt.Errorf("expected number, got %T", v)}
+	}
+	return f, nil
+}
+
+// GetInt returns an integer. Floats are accepted if they have no fraction
+// and fit in an int.
+func (e Event) GetInt(key string) (int, error) {
+	f, err := e.GetFloat(key)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := ToInt(f)
+	if !ok {
+		return 0, &PayloadError{Key: key, Err: fmt.Errorf("expected int, got %v", f)}
+	}
+	return n, nil
+}
+
+// GetString returns a string.
+func (e Event) GetString(key string) (string, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", &PayloadError{Key: key, Err: fmt.Errorf("expected string, got %T", v)}
+	}
+	return s, nil
+}
+
+// GetEntity returns an entity, stored either as entity.Entity or as its
+// numeric ID.
+func (e Event) GetEntity(key string) (entity.Entity, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return entity.Null, err
+	}
+	if ent, ok := v.(entity.Entity); ok {
+		return ent, nil
+	}
+	if f, ok := ToFloat(v); ok && f >= 0 && f < 1<<64 && f == math.Trunc(f) {
+		return entity.Entity(f), nil
+	}
+	return entity.Null, &PayloadError{Key: key, Err: fmt.Errorf("expected entity, got %T", v)}
+}
+
+// GetVec2 returns a vector, stored as Vec2, as {"x": .., "y": ..} or as a
+// two-element list.
+func (e Event) GetVec2(key string) (Vec2, error) {
+	v, err := e.lookup(key)
+	if err != nil {
+		return Vec2{}, err
+	}
+	if vec, ok := toVec2(v); ok {
+		return vec, nil
+	}
+	return Vec2{}, &PayloadError{Key: key, Err: fmt.Errorf("expected vec2, got %T", v)}
+}
+
+func (e Event) lookup(key string) (any, error) {
+	v, ok := e.Payload[key]
+	if !ok || v == nil {
+		return nil, &PayloadError{Key: key, Err: ErrMissing}
+	}
+	return v, nil
+}
+
+// ToFloat converts any Go number, including named types such as
+// time.Duration, to float64.
+func ToFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// ToInt converts any Go number holding a whole value within int's range to
+// int. NaN, infinities and fractions are rejected.
+func ToInt(v any) (int, bool) {
+	if n, ok := v.(int); ok {
+		return n, true
+	}
+	f, ok := ToFloat(v)
+	// -math.MinInt is the first float past the top of int's range;
+	// math.MaxInt itself rounds up to it.
+	if !ok || f != math.Trunc(f) || f < math.MinInt || f >= -math.MinInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
+func toVec2(v any) (Vec2, bool) {
+	switch vec := v.(type) {
+	case Vec2:
+		return vec, true
+	case *Vec2:
+		return *vec, vec != nil
+	case map[string]any:
+		x, okX := ToFloat(vec["x"])
+		y, okY := ToFloat(vec["y"])
+		return Vec2{X: x, Y: y}, okX && okY
+	case []any:
+		if len(vec) != 2 {
+			return Vec2{}, false
+		}
+		x, okX := ToFloat(vec[0])
+		y, okY := ToFloat(vec[1])
+		return Vec2{X: x, Y: y}, okX && okY
+	case []float64:
+		if len(vec) != 2 {
+			return Vec2{}, false
+		}
+		return Vec2{X: vec[0], Y: vec[1]}, true
+	}
+	return Vec2{}, false
+}
+
+// PayloadType is the expected type of a payload field.
+type PayloadType string
+
+const (
+	PayloadNumber PayloadType = "number"
+	PayloadInt    PayloadType = "int"
+	PayloadString PayloadType = "string"
+	PayloadEntity PayloadType = "entity"
+	PayloadVec2   PayloadType = "vec2"
+)
+
+// PayloadField describes one payload key of an event type.
+type PayloadField struct {
+	Key      string
+	Type     PayloadType
+	Required bool
+}
+
+// PayloadSchema lists the payload fields of an event type. Keys not listed
+// are allowed.
+type PayloadSchema []PayloadField
+
+// Validate checks ev's payload against the schema using the typed getters.
+func (s PayloadSchema) Validate(ev Event) error {
+	for _, f := range s {
+		if v, ok := ev.Payload[f.Key]; !ok || v == nil {
+			if f.Required {
+				return &PayloadError{Key: f.Key, Err: ErrMissing}
+			}
+			continue
+		}
+		var err error
+		switch f.Type {
+		case PayloadNumber:
+			_, err = ev.GetFloat(f.Key)
+		case PayloadInt:
+			_, err = ev.GetInt(f.Key)
+		case PayloadString:
+			_, err = ev.GetString(f.Key)
+		case PayloadEntity:
+			_, err = ev.GetEntity(f.Key)
+		case PayloadVec2:
+			_, err = ev.GetVec2(f.Key)
+		default:
+			err = &PayloadError{Key: f.Key, Err: fmt.Errorf("unknown payload type %q", f.Type)}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// ============================================
+// Getter Tests
+// ============================================
+
+func TestGetNumbersCoerce(t *testing.T) {
+	ev := Event{Payload: map[string]any{
+		"int":      20,
+		"float":    2.5,
+		"json":     float64(15),
+		"uint8":    uint8(7),
+		"duration": time.Duration(3),
+	}}
+
+	if f, err := ev.GetFloat("int"); err != nil || f != 20 {
+		t.Errorf("GetFloat(int) = %v, %v", f, err)
+	}
+	if i, err := ev.GetInt("json"); err != nil || i != 15 {
+		t.Errorf("GetInt(json) = %v, %v", i, err)
+	}
+	if i, err := ev.GetInt("uint8"); err != nil || i != 7 {
+		t.Errorf("GetInt(uint8) = %v, %v", i, err)
+	}
+	if i, err := ev.GetInt("duration"); err != nil || i != 3 {
+		t.Errorf("GetInt(duration) = %v, %v", i, err)
+	}
+	if _, err := ev.GetInt("float"); err == nil {
+		t.Error("GetInt should reject 2.5")
+	}
+}
+
+func TestGetIntRejectsOutOfRange(t *testing.T) {
+	ev := Event{Payload: map[string]any{
+		"huge": 1e30,
+		"inf":  math.Inf(1),
+		"ninf": math.Inf(-1),
+		"nan":  math.NaN(),
+	}}
+
+	for key := range ev.Payload {
+		if n, err := ev.GetInt(key); err == nil {
+			t.Errorf("GetInt(%s) = %d, expected an error", key, n)
+		}
+		if e, err := ev.GetEntity(key); err == nil {
+			t.Errorf("GetEntity(%s) = %v, expected an error", key, e)
+		}
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	ev := Event{Payload: map[string]any{"name": "boom", "nil": nil}}
+
+	_, err := ev.GetFloat("missing")
+	var pe *PayloadError
+	if !errors.As(err, &pe) || pe.Key != "missing" || !errors.Is(err, ErrMissing) {
+		t.Errorf("Expected missing PayloadError, got %v", err)
+	}
+	if _, err := ev.GetString("nil"); !errors.Is(err, ErrMissing) {
+		t.Errorf("nil values should count as missing, got %v", err)
+	}
+	_, err = ev.GetFloat("name")
+	if err == nil || err.Error() != `payload "name": expected number, got string` {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if _, err := (Event{}).GetString("any"); !errors.Is(err, ErrMissing) {
+		t.Error("A nil payload should report missing keys")
+	}
+}
+
+func TestGetEntity(t *testing.T) {
+	e := entity.NewEntity(3, 2)
+	ev := Event{Payload: map[string]any{
+		"direct": e,
+		"json":   float64(e),
+		"bad":    -1,
+	}}
+
+	if got, err := ev.GetEntity("direct"); err != nil || got != e {
+		t.Errorf("GetEntity(direct) = %v, %v", got, err)
+	}
+	if got, err := ev.GetEntity("json"); err != nil || got != e {
+		t.Errorf("GetEntity(json) = %v, %v", got, err)
+	}
+	if _, err := ev.GetEntity("bad"); err == nil {
+		t.Error("Negative IDs should be rejected")
+	}
+}
+
+func TestGetVec2(t *testing.T) {
+	var decoded map[string]any
+	json.Unmarshal([]byte(`{"dir": {"x": 1, "y": -1}, "list": [3, 4], "short": [1]}`), &decoded)
+	decoded["vec"] = Vec2{X: 5, Y: 6}
+	ev := Event{Payload: decoded}
+
+	cases := map[string]Vec2{"dir": {1, -1}, "list": {3, 4}, "vec": {5, 6}}
+	for key, want := range cases {
+		if got, err := ev.GetVec2(key); err != nil || got != want {
+			t.Errorf("GetVec2(%s) = %v, %v; want %v", key, got, err, want)
+		}
+	}
+	if _, err := ev.GetVec2("short"); err == nil {
+		t.Error("A one-element list is not a vec2")
+	}
+}
+
+// ============================================
+// Schema Tests
+// ============================================
+
+func TestPayloadSchemaValidate(t *testing.T) {
+	schema := PayloadSchema{
+		{Key: "damage", Type: PayloadInt, Required: true},
+		{Key: "source", Type: PayloadEntity},
+	}
+
+	if err := schema.Validate(Event{Payload: map[string]any{"damage": 10.0, "extra": "ok"}}); err != nil {
+		t.Errorf("Valid payload rejected: %v", err)
+	}
+	if err := schema.Validate(Event{}); !errors.Is(err, ErrMissing) {
+		t.Errorf("Expected missing damage, got %v", err)
+	}
+	if err := schema.Validate(Event{Payload: map[string]any{"damage": 1, "source": "me"}}); err == nil {
+		t.Error("Expected a type error for source")
+	}
+}
@@ -1,56 +1,162 @@
 package entity
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // Entity is just a numeric ID - lightweight and fast.
+//
+// The low 32 bits hold the slot index and the high 32 bits hold the
+// generation of that slot. When an entity is destroyed its index is recycled
+// with a bumped generation, so stale handles never compare equal to the new
+// entity living in the same slot.
 type Entity uint64
 
+// Null is the zero Entity. Generations start at 1, so it never refers to a live entity.
+const Null Entity = 0
+
+// NewEntity builds an Entity from a slot index and a generation.
+func NewEntity(index, generation uint32) Entity {
+	return Entity(uint64(generation)<<32 | uint64(index))
+}
+
+// Index returns the slot index of the entity.
+func (e Entity) Index() uint32 {
+	return uint32(e)
+}
+
+// Generation returns how many times the entity's slot has been used.
+func (e Entity) Generation() uint32 {
+	return uint32(e >> 32)
+}
+
+// ComponentStore is the type-erased view of a component storage.
+// Stores registered with a World are purged when entities are destroyed.
+// components.ComponentManager implements it.
+type ComponentStore interface {
+	Has(e Entity) bool
+	Remove(e Entity)
+	Count() int
+}
+
+// ComponentCodec is a ComponentStore whose components convert to and from
+// JSON. Scene files and prefabs are loaded through it.
+type ComponentCodec interface {
+	ComponentStore
+	MarshalComponent(e Entity) ([]byte, error)
+	UnmarshalComponent(e Entity, data []byte) error
+}
+
 // World manages entity lifecycle: creation, destruction, and cleanup.
 type World struct {
-	nextID           Entity
-	alive            map[Entity]bool
+	generations      []uint32 // index -> current generation of the slot
+	alive            []bool   // index -> slot holds a live (not destroyed) entity
+	freeIndices      []uint32 // recycled slots, reused before growing
+	count            int
 	entitiesToDelete []Entity
 	tags             *TagManager
+	components       map[string]ComponentStore
+	componentNames   []string // registration order, keeps Cleanup deterministic
+	onCreated        []EntityHook
+	onDestroyed      []EntityHook
+	prefabs          map[string]Prefab
 }
 
+// EntityHook is called when an entity is created or destroyed.
+type EntityHook func(e Entity)
+
 // NewWorld creates a new game world.
 func NewWorld() *World {
 	return &World{
-		nextID:           0,
-		alive:            make(map[Entity]bool),
+		generations:      make([]uint32, 0),
+		alive:            make([]bool, 0),
+		freeIndices:      make([]uint32, 0),
 		entitiesToDelete: make([]Entity, 0),
 		tags:             NewTagManager(),
+		components:       make(map[string]ComponentStore),
 	}
 }
 
-// CreateEntity creates a new entity with a unique ID.
+// CreateEntity creates a new entity, reusing a freed slot when one is available.
 func (w *World) CreateEntity(tags ...string) Entity {
-	id := w.nextID
-	w.nextID++
-	w.alive[id] = true
+	var index uint32
+	if n := len(w.freeIndices); n > 0 {
+		index = w.freeIndices[n-1]
+		w.freeIndices = w.freeIndices[:n-1]
+	} else {
+		index = uint32(len(w.generations))
+		w.generations = append(w.generations, 1)
+		w.alive = append(w.alive, false)
+	}
+
+	id := NewEntity(index, w.generations[index])
+	w.alive[index] = true
+	w.count++
 	for _, tag := range tags {
 		w.tags.AddTag(id, tag)
 	}
+	for _, hook := range w.onCreated {
+		hook(id)
+	}
 	return id
 }
 
 // DestroyEntity marks an entity for deletion (removed at end of frame).
 func (w *World) DestroyEntity(e Entity) {
-	if !w.alive[e] {
+	if !w.IsAlive(e) {
 		return
 	}
-	w.alive[e] = false
+	w.alive[e.Index()] = false
+	w.count--
 	w.entitiesToDelete = append(w.entitiesToDelete, e)
+	for _, hook := range w.onDestroyed {
+		hook(e)
+	}
+}
+
+// OnEntityCreated registers a hook called after CreateEntity, once the
+// entity's initial tags are set.
+func (w *World) OnEntityCreated(hook EntityHook) {
+	w.onCreated = append(w.onCreated, hook)
 }
 
-// IsAlive checks if an entity exists and is not marked for deletion.
+// OnEntityDestroyed registers a hook called when DestroyEntity marks an
+// entity for deletion. Its tags and components are still readable until
+// Cleanup.
+func (w *World) OnEntityDestroyed(hook EntityHook) {
+	w.onDestroyed = append(w.onDestroyed, hook)
+}
+
+// IsAlive checks if an entity exists, is not marked for deletion and is not
+// a stale handle to a recycled slot.
 func (w *World) IsAlive(e Entity) bool {
-	return w.alive[e]
+	index := e.Index()
+	if int(index) >= len(w.generations) {
+		return false
+	}
+	return w.alive[index] && w.generations[index] == e.Generation()
 }
 
-// Cleanup removes all entities marked for deletion. Call at end of frame.
+// Cleanup removes all entities marked for deletion and frees their slots for
+// reuse. Call at end of frame.
 func (w *World) Cleanup() {
 	for _, e := range w.entitiesToDelete {
 		w.tags.RemoveAllTags(e)
-		delete(w.alive, e)
+		for _, name := range w.componentNames {
+			if store := w.components[name]; store.Has(e) {
+				store.Remove(e)
+			}
+		}
+
+		index := e.Index()
+		w.generations[index]++
+		if w.generations[index] == 0 {
+			// Wrapped around: skip 0 so Null stays invalid.
+			w.generations[index] = 1
+		}
+		w.freeIndices = append(w.freeIndices, index)
 	}
 	w.entitiesToDelete = w.entitiesToDelete[:0]
 }
@@ -60,13 +166,43 @@ func (w *World) Tags() *TagManager {
 	return w.tags
 }
 
-// EntityCount returns the number of alive entities.
-func (w *World) EntityCount() int {
-	count := 0
-	for _, isAlive := range w.alive {
+// Entities returns all alive entities sorted by entity ID.
+func (w *World) Entities() []Entity {
+	result := make([]Entity, 0, w.count)
+	for index, isAlive := range w.alive {
 		if isAlive {
-			count++
+			result = append(result, NewEntity(uint32(index), w.generations[index]))
 		}
 	}
-	return count
+	slices.Sort(result)
+	return result
+}
+
+// EntityCount returns the number of alive entities.
+func (w *World) EntityCount() int {
+	return w.count
+}
+
+// RegisterComponent adds a component store to the world's registry under a
+// case-insensitive name. Registered stores lose their data for an entity
+// when it is cleaned up. Registering the same name twice panics.
+func (w *World) RegisterComponent(name string, store ComponentStore) {
+	name = strings.ToLower(name)
+	if _, exists := w.components[name]; exists {
+		panic(fmt.Sprintf("entity: component %q registered twice", name))
+	}
+	w.components[name] = store
+	w.componentNames = append(w.componentNames, name)
+}
+
+// Component returns the store registered under name, or nil if none.
+func (w *World) Component(name string) ComponentStore {
+	return w.components[strings.ToLower(name)]
+}
+
+// ComponentNames returns the registered component names in registration order.
+func (w *World) ComponentNames() []string {
+	names := make([]string, len(w.componentNames))
+	copy(names, w.componentNames)
+	return names
 }
@@ -1,6 +1,11 @@
 package entity
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
 
 // ============================================
 // Entity Creation Tests
@@ -11,8 +16,11 @@ func TestCreateEntity(t *testing.T) {
 
 	player := world.CreateEntity("player")
 
-	if player != 0 {
-		t.Errorf("Expected entity 0, got %d", player)
+	if player.Index() != 0 {
+		t.Errorf("Expected index 0, got %d", player.Index())
+	}
+	if player == Null {
+		t.Error("New entity should never be Null")
 	}
 	if !world.IsAlive(player) {
 		t.Error("New entity should be alive")
@@ -26,8 +34,8 @@ func TestCreateMultipleEntities(t *testing.T) {
 	e1 := world.CreateEntity("enemy")
 	e2 := world.CreateEntity("bullet")
 
-	if e0 != 0 || e1 != 1 || e2 != 2 {
-		t.Errorf("Expected 0, 1, 2 - got %d, %d, %d", e0, e1, e2)
+	if e0.Index() != 0 || e1.Index() != 1 || e2.Index() != 2 {
+		t.Errorf("Expected indices 0, 1, 2 - got %d, %d, %d", e0.Index(), e1.Index(), e2.Index())
 	}
 }
 
@@ -308,3 +316,415 @@ func TestDestroyNonExistent(t *testing.T) {
 	// Should not panic
 	world.DestroyEntity(Entity(999))
 }
+
+// ============================================
+// Recycling Tests
+// ============================================
+
+func TestEntityIndexAndGeneration(t *testing.T) {
+	e := NewEntity(7, 3)
+
+	if e.Index() != 7 {
+		t.Errorf("Expected index 7, got %d", e.Index())
+	}
+	if e.Generation() != 3 {
+		t.Errorf("Expected generation 3, got %d", e.Generation())
+	}
+}
+
+func TestIndexRecycledAfterCleanup(t *testing.T) {
+	world := NewWorld()
+
+	old := world.CreateEntity("enemy")
+	world.DestroyEntity(old)
+	world.Cleanup()
+
+	recycled := world.CreateEntity("enemy")
+
+	if recycled.Index() != old.Index() {
+		t.Errorf("Expected index %d to be reused, got %d", old.Index(), recycled.Index())
+	}
+	if recycled.Generation() != old.Generation()+1 {
+		t.Errorf("Expected generation %d, got %d", old.Generation()+1, recycled.Generation())
+	}
+	if recycled == old {
+		t.Error("Recycled entity must differ from the stale handle")
+	}
+}
+
+func TestIndexNotRecycledBeforeCleanup(t *testing.T) {
+	world := NewWorld()
+
+	old := world.CreateEntity()
+	world.DestroyEntity(old)
+	e := world.CreateEntity()
+
+	if e.Index() == old.Index() {
+		t.Error("Index should not be reused until Cleanup")
+	}
+}
+
+func TestStaleHandleNotAlive(t *testing.T) {
+	world := NewWorld()
+
+	old := world.CreateEntity("enemy")
+	world.DestroyEntity(old)
+	world.Cleanup()
+	recycled := world.CreateEntity("enemy")
+
+	if world.IsAlive(old) {
+		t.Error("Stale handle should not be alive after its slot was recycled")
+	}
+	if !world.IsAlive(recycled) {
+		t.Error("Recycled entity should be alive")
+	}
+
+	// Destroying through a stale handle must not affect the new entity
+	world.DestroyEntity(old)
+	if !world.IsAlive(recycled) {
+		t.Error("DestroyEntity with a stale handle killed the recycled entity")
+	}
+}
+
+func TestRecyclingBoundsStorage(t *testing.T) {
+	world := NewWorld()
+
+	for frame := 0; frame < 100; frame++ {
+		for i := 0; i < 10; i++ {
+			world.DestroyEntity(world.CreateEntity("bullet"))
+		}
+		world.Cleanup()
+	}
+
+	if len(world.generations) != 10 {
+		t.Errorf("Expected 10 slots after churn, got %d", len(world.generations))
+	}
+	if world.EntityCount() != 0 {
+		t.Errorf("Expected 0 entities, got %d", world.EntityCount())
+	}
+}
+
+func TestNullNeverAlive(t *testing.T) {
+	world := NewWorld()
+	world.CreateEntity()
+
+	if world.IsAlive(Null) {
+		t.Error("Null should never be alive")
+	}
+}
+
+// ============================================
+// Component Registry Tests
+// ============================================
+
+// fakeStore is a minimal ComponentStore for registry tests.
+type fakeStore struct {
+	data map[Entity]bool
+}
+
+func (s *fakeStore) Has(e Entity) bool { return s.data[e] }
+func (s *fakeStore) Remove(e Entity)   { delete(s.data, e) }
+func (s *fakeStore) Count() int        { return len(s.data) }
+
+func TestRegisterComponent(t *testing.T) {
+	world := NewWorld()
+	store := &fakeStore{data: map[Entity]bool{}}
+
+	world.RegisterComponent("Health", store)
+
+	if world.Component("health") != store {
+		t.Error("Component lookup should be case-insensitive")
+	}
+	if world.Component("mana") != nil {
+		t.Error("Unknown component should return nil")
+	}
+	names := world.ComponentNames()
+	if len(names) != 1 || names[0] != "health" {
+		t.Errorf("Expected [health], got %v", names)
+	}
+}
+
+func TestRegisterComponentTwicePanics(t *testing.T) {
+	world := NewWorld()
+	world.RegisterComponent("health", &fakeStore{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Registering a component twice should panic")
+		}
+	}()
+	world.RegisterComponent("HEALTH", &fakeStore{})
+}
+
+func TestCleanupPurgesComponents(t *testing.T) {
+	world := NewWorld()
+	store := &fakeStore{data: map[Entity]bool{}}
+	world.RegisterComponent("health", store)
+
+	player := world.CreateEntity("player")
+	enemy := world.CreateEntity("enemy")
+	store.data[player] = true
+	store.data[enemy] = true
+
+	world.DestroyEntity(enemy)
+
+	if !store.Has(enemy) {
+		t.Error("Component data should survive until Cleanup")
+	}
+
+	world.Cleanup()
+
+	if store.Has(enemy) {
+		t.Error("Cleanup should purge components of destroyed entities")
+	}
+	if !store.Has(player) {
+		t.Error("Cleanup should keep components of alive entities")
+	}
+}
+
+// ============================================
+// Prefab Tests
+// ============================================
+
+// rawStore records the JSON each entity was decoded from.
+type rawStore struct {
+	data map[Entity]string
+}
+
+func (s *rawStore) Has(e Entity) bool { _, ok := s.data[e]; return ok }
+func (s *rawStore) Remove(e Entity)   { delete(s.data, e) }
+func (s *rawStore) Count() int        { return len(s.data) }
+
+func (s *rawStore) MarshalComponent(e Entity) ([]byte, error) {
+	return []byte(s.data[e]), nil
+}
+
+func (s *rawStore) UnmarshalComponent(e Entity, data []byte) error {
+	if string(data) == "bad" {
+		return errors.New("cannot decode")
+	}
+	s.data[e] = string(data)
+	return nil
+}
+
+func TestSpawnPrefab(t *testing.T) {
+	world := NewWorld()
+	store := &rawStore{data: map[Entity]string{}}
+	world.RegisterComponent("health", store)
+	world.DefinePrefab("Grunt", Prefab{
+		Tags:       []string{"enemy"},
+		Components: map[string]json.RawMessage{"health": json.RawMessage(`{"current":3}`)},
+	})
+
+	e, err := world.Spawn("grunt", "wave.1")
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	if !world.Tags().HasTag(e, "enemy") || !world.Tags().HasTag(e, "wave.1") {
+		t.Errorf("Expected prefab and extra tags, got %v", world.Tags().GetTags(e))
+	}
+	if store.data[e] != `{"current":3}` {
+		t.Errorf("Component not decoded, got %q", store.data[e])
+	}
+	if names := world.PrefabNames(); !slices.Equal(names, []string{"grunt"}) {
+		t.Errorf("Expected [grunt], got %v", names)
+	}
+}
+
+func TestSpawnPrefabErrors(t *testing.T) {
+	world := NewWorld()
+	world.RegisterComponent("health", &rawStore{data: map[Entity]string{}})
+	world.RegisterComponent("flag", &fakeStore{data: map[Entity]bool{}})
+	world.DefinePrefab("ghost", Prefab{Components: map[string]json.RawMessage{"mana": nil}})
+	world.DefinePrefab("flagged", Prefab{Components: map[string]json.RawMessage{"flag": nil}})
+	world.DefinePrefab("broken", Prefab{Components: map[string]json.RawMessage{"health": json.RawMessage("bad")}})
+
+	for _, name := range []string{"missing", "ghost", "flagged", "broken"} {
+		if e, err := world.Spawn(name); err == nil || e != Null {
+			t.Errorf("Spawn(%q) should fail, got %v, %v", name, e, err)
+		}
+	}
+	if world.EntityCount() != 0 {
+		t.Errorf("Failed spawns should leave no entities, got %d", world.EntityCount())
+	}
+}
+
+// ============================================
+// Determinism Tests
+// ============================================
+
+// buildTaggedWorld creates and destroys entities in a fixed pattern.
+func buildTaggedWorld() *World {
+	world := NewWorld()
+	for i := 0; i < 50; i++ {
+		e := world.CreateEntity("enemy")
+		if i%4 == 0 {
+			world.DestroyEntity(e)
+		}
+	}
+	world.Cleanup()
+	for i := 0; i < 10; i++ {
+		world.CreateEntity("enemy", "respawned")
+	}
+	return world
+}
+
+func TestGetEntitiesByTagIsRepeatable(t *testing.T) {
+	first := buildTaggedWorld().Tags().GetEntitiesByTag("enemy")
+
+	for i := 0; i < 20; i++ {
+		again := buildTaggedWorld().Tags().GetEntitiesByTag("enemy")
+		if !slices.Equal(first, again) {
+			t.Fatalf("Run %d returned %v, first run returned %v", i, again, first)
+		}
+	}
+	if !slices.IsSorted(first) {
+		t.Errorf("GetEntitiesByTag should be sorted by entity ID, got %v", first)
+	}
+}
+
+func TestGetTagsIsSorted(t *testing.T) {
+	world := NewWorld()
+	e := world.CreateEntity("zombie", "alpha", "miner", "beta")
+
+	for i := 0; i < 20; i++ {
+		tags := world.Tags().GetTags(e)
+		want := []string{"alpha", "beta", "miner", "zombie"}
+		if !slices.Equal(tags, want) {
+			t.Fatalf("Expected %v, got %v", want, tags)
+		}
+	}
+}
+
+// ============================================
+// Hierarchical Tag Tests
+// ============================================
+
+func TestHierarchicalTagNormalization(t *testing.T) {
+	world := NewWorld()
+	e := world.CreateEntity("Enemy.Flying.Bat", "..pickup..coin.", "!!!.")
+
+	tags := world.Tags().GetTags(e)
+	want := []string{"enemy.flying.bat", "pickup.coin"}
+	if !slices.Equal(tags, want) {
+		t.Errorf("Expected %v, got %v", want, tags)
+	}
+}
+
+func TestHierarchicalHasTag(t *testing.T) {
+	world := NewWorld()
+	bat := world.CreateEntity("enemy.flying.bat")
+
+	for _, tag := range []string{"enemy", "enemy.flying", "enemy.flying.bat"} {
+		if !world.Tags().HasTag(bat, tag) {
+			t.Errorf("HasTag(%q) should be true for enemy.flying.bat", tag)
+		}
+	}
+	if world.Tags().HasTag(bat, "enemy.flying.bat.baby") {
+		t.Error("HasTag should not match a deeper tag")
+	}
+	if world.Tags().HasTag(bat, "enemy.fly") {
+		t.Error("HasTag should match whole segments only")
+	}
+	if world.Tags().HasExactTag(bat, "enemy") {
+		t.Error("HasExactTag should ignore descendants")
+	}
+}
+
+func TestHierarchicalGetEntitiesByTag(t *testing.T) {
+	world := NewWorld()
+	bat := world.CreateEntity("enemy.flying.bat")
+	crow := world.CreateEntity("enemy.flying.crow")
+	slime := world.CreateEntity("enemy.ground.slime")
+	world.CreateEntity("pickup")
+
+	if got := world.Tags().GetEntitiesByTag("enemy"); !slices.Equal(got, []Entity{bat, crow, slime}) {
+		t.Errorf("Expected all enemies, got %v", got)
+	}
+	if got := world.Tags().GetEntitiesByTag("enemy.flying"); !slices.Equal(got, []Entity{bat, crow}) {
+		t.Errorf("Expected flying enemies, got %v", got)
+	}
+	if got, _ := world.Tags().Query("enemy & !enemy.flying"); !slices.Equal(got, []Entity{slime}) {
+		t.Errorf("Expected only the slime, got %v", got)
+	}
+}
+
+func TestHierarchicalRemoveKeepsCoveredAncestors(t *testing.T) {
+	world := NewWorld()
+	e := world.CreateEntity("enemy.flying.bat", "enemy.boss")
+
+	world.Tags().RemoveTag(e, "enemy.flying.bat")
+
+	if world.Tags().HasTag(e, "enemy.flying") {
+		t.Error("enemy.flying should be gone with its only descendant")
+	}
+	if !world.Tags().HasTag(e, "enemy") {
+		t.Error("enemy should remain while enemy.boss is present")
+	}
+
+	world.Tags().RemoveTag(e, "enemy.boss")
+
+	if world.Tags().HasTag(e, "enemy") {
+		t.Error("enemy should be gone once no descendant remains")
+	}
+}
+
+func TestHierarchicalCleanup(t *testing.T) {
+	world := NewWorld()
+	bat := world.CreateEntity("enemy.flying.bat")
+	world.DestroyEntity(bat)
+	world.Cleanup()
+
+	if n := world.Tags().CountByTag("enemy"); n != 0 {
+		t.Errorf("Expected 0 enemies after cleanup, got %d", n)
+	}
+}
+
+func TestEntitiesListsAliveSorted(t *testing.T) {
+	world := NewWorld()
+	a := world.CreateEntity()
+	b := world.CreateEntity()
+	c := world.CreateEntity()
+	world.DestroyEntity(a)
+	world.Cleanup()
+	d := world.CreateEntity() // reuses a's slot with a newer generation
+
+	got := world.Entities()
+	want := []Entity{b, c, d}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// ============================================
+// Lifecycle Hook Tests
+// ============================================
+
+func TestEntityHooks(t *testing.T) {
+	world := NewWorld()
+	var created, destroyed []Entity
+	world.OnEntityCreated(func(e Entity) {
+		if !world.Tags().HasTag(e, "enemy") {
+			t.Error("Initial tags should be set before OnEntityCreated runs")
+		}
+		created = append(created, e)
+	})
+	world.OnEntityDestroyed(func(e Entity) {
+		if !world.Tags().HasTag(e, "enemy") {
+			t.Error("Tags should still be readable in OnEntityDestroyed")
+		}
+		destroyed = append(destroyed, e)
+	})
+
+	e := world.CreateEntity("enemy")
+	world.DestroyEntity(e)
+	world.DestroyEntity(e) // already destroyed, no second call
+	world.Cleanup()
+
+	if !slices.Equal(created, []Entity{e}) {
+		t.Errorf("Expected created %v, got %v", []Entity{e}, created)
+	}
+	if !slices.Equal(destroyed, []Entity{e}) {
+		t.Errorf("Expected destroyed %v, got %v", []Entity{e}, destroyed)
+	}
+}
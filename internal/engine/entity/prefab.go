@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Prefab is a named entity template: the tags and JSON component data every
+// spawned copy starts with. Components use the same keys and encoding as a
+// scene file.
+type Prefab struct {
+	Tags       []string                   `json:"tags,omitempty"`
+	Components map[string]json.RawMessage `json:"components,omitempty"`
+}
+
+// DefinePrefab registers p under a case-insensitive name, replacing any
+// prefab defined before under that name.
+func (w *World) DefinePrefab(name string, p Prefab) {
+	if w.prefabs == nil {
+		w.prefabs = make(map[string]Prefab)
+	}
+	w.prefabs[strings.ToLower(name)] = p
+}
+
+// Prefab returns the prefab defined under name.
+func (w *World) Prefab(name string) (Prefab, bool) {
+	p, ok := w.prefabs[strings.ToLower(name)]
+	return p, ok
+}
+
+// PrefabNames returns the defined prefab names, sorted.
+func (w *World) PrefabNames() []string {
+	names := make([]string, 0, len(w.prefabs))
+	for name := range w.prefabs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Spawn creates an entity from the prefab defined under name, with the
+// prefab's tags plus any extra tags. Component names are checked before the
+// entity is created; if a component fails to decode the entity is destroyed
+// again and the error returned.
+func (w *World) Spawn(name string, tags ...string) (Entity, error) {
+	p, ok := w.Prefab(name)
+	if !ok {
+		return Null, fmt.Errorf("unknown prefab %q", name)
+	}
+
+	names := make([]string, 0, len(p.Components))
+	for comp := range p.Components {
+		if _, ok := w.Component(comp).(ComponentCodec); !ok {
+			return Null, fmt.Errorf("prefab %q: component %q is not registered or cannot be decoded", name, comp)
+		}
+		names = append(names, comp)
+	}
+	slices.Sort(names)
+
+	e := w.CreateEntity(append(slices.Clone(p.Tags), tags...)...)
+	for _, comp := range names {
+		store := w.Component(comp).(ComponentCodec)
+		if err := store.UnmarshalComponent(e, p.Components[comp]); err != nil {
+			w.DestroyEntity(e)
+			return Null, fmt.Errorf("prefab %q: components.%s: %w", name, comp, err)
+		}
+	}
+	return e, nil
+}
@@ -0,0 +1,333 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TagExpr is a boolean expression over tags, such as "enemy & !boss | pickup".
+// Build one with ParseTagExpr or programmatically with Tag, And, Or and Not.
+type TagExpr interface {
+	// eval returns the set of matching entities. The result may be one of
+	// the TagManager's own index maps and must not be modified.
+	eval(tm *TagManager) map[Entity]bool
+	// match reports whether a single entity satisfies the expression.
+	match(tm *TagManager, e Entity) bool
+	// String returns the expression in ParseTagExpr syntax.
+	String() string
+}
+
+// Tag matches entities carrying the tag.
+func Tag(name string) TagExpr {
+	return tagExpr(filterTag(name))
+}
+
+// And matches entities satisfying every expression.
+func And(exprs ...TagExpr) TagExpr {
+	return andExpr(exprs)
+}
+
+// Or matches entities satisfying at least one expression.
+func Or(exprs ...TagExpr) TagExpr {
+	return orExpr(exprs)
+}
+
+// Not matches entities that do not satisfy expr. On its own it is evaluated
+// against every tagged entity; inside And it subtracts from the other terms.
+func Not(expr TagExpr) TagExpr {
+	return notExpr{expr}
+}
+
+// QueryExpr returns all entities matching expr, sorted by entity ID.
+func (tm *TagManager) QueryExpr(expr TagExpr) []Entity {
+	set := expr.eval(tm)
+	result := make([]Entity, 0, len(set))
+	for e := range set {
+		result = append(result, e)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Query parses a tag expression and returns all matching entities.
+//
+// Usage:
+//
+//	targets, err := world.Tags().Query("enemy & !stunned | pickup")
+func (tm *TagManager) Query(expr string) ([]Entity, error) {
+	parsed, err := ParseTagExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	return tm.QueryExpr(parsed), nil
+}
+
+// MatchesExpr reports whether a single entity satisfies expr. Like
+// QueryExpr, it only considers tagged entities, so an entity without tags
+// matches nothing, not even Not(Tag("boss")).
+func (tm *TagManager) MatchesExpr(e Entity, expr TagExpr) bool {
+	return len(tm.entityTags[e]) > 0 && expr.match(tm, e)
+}
+
+// universe returns every entity that has at least one tag.
+func (tm *TagManager) universe() map[Entity]bool {
+	set := make(map[Entity]bool, len(tm.entityTags))
+	for e, tags := range tm.entityTags {
+		if len(tags) > 0 {
+			set[e] = true
+		}
+	}
+	return set
+}
+
+// ============================================
+// Expression nodes
+// ============================================
+
+type tagExpr string
+
+func (t tagExpr) eval(tm *TagManager) map[Entity]bool {
+	return tm.tagIndex[string(t)]
+}
+
+func (t tagExpr) match(tm *TagManager, e Entity) bool {
+	return tm.tagIndex[string(t)][e]
+}
+
+func (t tagExpr) String() string {
+	return string(t)
+}
+
+type andExpr []TagExpr
+
+// eval intersects the positive terms starting from the smallest set, then
+// subtracts the negated ones.
+func (a andExpr) eval(tm *TagManager) map[Entity]bool {
+	var include, exclude []map[Entity]bool
+	for _, expr := range a {
+		if not, ok := expr.(notExpr); ok {
+			exclude = append(exclude, not.expr.eval(tm))
+		} else {
+			include = append(include, expr.eval(tm))
+		}
+	}
+	if len(include) == 0 {
+		include = append(include, tm.universe())
+	}
+
+	smallest := 0
+	for i, set := range include {
+		if len(set) < len(include[smallest]) {
+			smallest = i
+		}
+	}
+
+	result := make(map[Entity]bool)
+outer:
+	for e := range include[smallest] {
+		for i, set := range include {
+			if i != smallest && !set[e] {
+				continue outer
+			}
+		}
+		for _, set := range exclude {
+			if set[e] {
+				continue outer
+			}
+		}
+		result[e] = true
+	}
+	return result
+}
+
+func (a andExpr) match(tm *TagManager, e Entity) bool {
+	for _, expr := range a {
+		if !expr.match(tm, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a andExpr) String() string {
+	return joinExprs(a, " & ")
+}
+
+type orExpr []TagExpr
+
+func (o orExpr) eval(tm *TagManager) map[Entity]bool {
+	result := make(map[Entity]bool)
+	for _, expr := range o {
+		for e := range expr.eval(tm) {
+			result[e] = true
+		}
+	}
+	return result
+}
+
+func (o orExpr) match(tm *TagManager, e Entity) bool {
+	for _, expr := range o {
+		if expr.match(tm, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o orExpr) String() string {
+	return joinExprs(o, " | ")
+}
+
+type notExpr struct {
+	expr TagExpr
+}
+
+func (n notExpr) eval(tm *TagManager) map[Entity]bool {
+	return andExpr{n}.eval(tm)
+}
+
+func (n notExpr) match(tm *TagManager, e Entity) bool {
+	return !n.expr.match(tm, e)
+}
+
+func (n notExpr) String() string {
+	switch n.expr.(type) {
+	case andExpr, orExpr:
+		return "!(" + n.expr.String() + ")"
+	}
+	return "!" + n.expr.String()
+}
+
+func joinExprs(exprs []TagExpr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = expr.String()
+		if _, ok := expr.(orExpr); ok && sep != " | " {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// ============================================
+// Parser
+// ============================================
+
+// ParseTagExpr parses a tag expression. Operators, from lowest to highest
+// precedence, are | (or), & (and) and ! (not); parentheses group.
+// Tag names are normalized the same way as in AddTag.
+func ParseTagExpr(s string) (TagExpr, error) {
+	p := &tagParser{src: s}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpaces(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+	return expr, nil
+}
+
+type tagParser struct {
+	src string
+	pos int
+}
+
+func (p *tagParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("entity: tag query %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *tagParser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// accept consumes op if it is the next non-space character.
+func (p *tagParser) accept(op byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.src) && p.src[p.pos] == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *tagParser) parseOr() (TagExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []TagExpr{left}
+	for p.accept('|') {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return orExpr(terms), nil
+}
+
+func (p *tagParser) parseAnd() (TagExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []TagExpr{left}
+	for p.accept('&') {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, right)
+	}
+	if len(terms) == 1 {
+		return left, nil
+	}
+	return andExpr(terms), nil
+}
+
+func (p *tagParser) parseUnary() (TagExpr, error) {
+	if p.accept('!') {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+	if p.accept('(') {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(')') {
+			return nil, p.errorf("missing ')'")
+		}
+		return expr, nil
+	}
+	return p.parseTag()
+}
+
+func (p *tagParser) parseTag() (TagExpr, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune("|&!() \t", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos == len(p.src) {
+			return nil, p.errorf("expected tag, got end of input")
+		}
+		return nil, p.errorf("expected tag, got %q", p.src[p.pos])
+	}
+	name := filterTag(p.src[start:p.pos])
+	if name == "" {
+		return nil, p.errorf("invalid tag %q", p.src[start:p.pos])
+	}
+	return tagExpr(name), nil
+}
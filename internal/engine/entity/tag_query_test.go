@@ -0,0 +1,160 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+// queryWorld returns a world with a small, known tag layout.
+func queryWorld() (*World, map[string]Entity) {
+	world := NewWorld()
+	named := map[string]Entity{
+		"grunt":   world.CreateEntity("enemy"),
+		"stunned": world.CreateEntity("enemy", "stunned"),
+		"boss":    world.CreateEntity("enemy", "boss"),
+		"coin":    world.CreateEntity("pickup"),
+		"player":  world.CreateEntity("player"),
+	}
+	return world, named
+}
+
+// ============================================
+// Parser Tests
+// ============================================
+
+func TestParseTagExprPrecedence(t *testing.T) {
+	cases := map[string]string{
+		"enemy":                   "enemy",
+		"enemy & !boss | pickup":  "enemy & !boss | pickup",
+		"enemy & (boss | pickup)": "enemy & (boss | pickup)",
+		"!(enemy | pickup)":       "!(enemy | pickup)",
+		"  Enemy&!BOSS  ":         "enemy & !boss",
+	}
+	for input, want := range cases {
+		expr, err := ParseTagExpr(input)
+		if err != nil {
+			t.Errorf("ParseTagExpr(%q) failed: %v", input, err)
+			continue
+		}
+		if expr.String() != want {
+			t.Errorf("ParseTagExpr(%q) = %q, want %q", input, expr.String(), want)
+		}
+	}
+}
+
+func TestParseTagExprErrors(t *testing.T) {
+	for _, input := range []string{"", "enemy &", "(enemy", "enemy)", "!!!", "enemy | | boss"} {
+		if _, err := ParseTagExpr(input); err == nil {
+			t.Errorf("ParseTagExpr(%q) should fail", input)
+		}
+	}
+}
+
+// ============================================
+// Query Tests
+// ============================================
+
+func TestQueryAndNotOr(t *testing.T) {
+	world, named := queryWorld()
+
+	got, err := world.Tags().Query("enemy & !boss | pickup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entity{named["grunt"], named["stunned"], named["coin"]}
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestQueryNotStunned(t *testing.T) {
+	world, named := queryWorld()
+
+	got, err := world.Tags().Query("enemy & !stunned")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entity{named["grunt"], named["boss"]}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestQueryTopLevelNot(t *testing.T) {
+	world, named := queryWorld()
+
+	got, err := world.Tags().Query("!enemy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entity{named["coin"], named["player"]}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestQueryExprBuilder(t *testing.T) {
+	world, named := queryWorld()
+
+	expr := Or(And(Tag("enemy"), Not(Tag("boss"))), Tag("pickup"))
+	got := world.Tags().QueryExpr(expr)
+
+	parsed, _ := world.Tags().Query(expr.String())
+	if !slices.Equal(got, parsed) {
+		t.Errorf("Builder and parser disagree: %v vs %v", got, parsed)
+	}
+	if len(got) != 3 || slices.Contains(got, named["boss"]) {
+		t.Errorf("Unexpected result %v", got)
+	}
+}
+
+func TestMatchesExpr(t *testing.T) {
+	world, named := queryWorld()
+	expr, _ := ParseTagExpr("enemy & !boss")
+
+	if !world.Tags().MatchesExpr(named["grunt"], expr) {
+		t.Error("grunt should match")
+	}
+	if world.Tags().MatchesExpr(named["boss"], expr) {
+		t.Error("boss should not match")
+	}
+}
+
+func TestMatchesExprAgreesWithQuery(t *testing.T) {
+	world, named := queryWorld()
+	untagged := world.CreateEntity()
+	stripped := world.CreateEntity("boss")
+	world.Tags().RemoveTag(stripped, "boss")
+
+	all := []Entity{untagged, stripped}
+	for _, e := range named {
+		all = append(all, e)
+	}
+	for _, src := range []string{"!boss", "enemy & !boss", "!(enemy | pickup)", "player | !enemy", "!!enemy"} {
+		expr, err := ParseTagExpr(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := world.Tags().QueryExpr(expr)
+		for _, e := range all {
+			if got, want := world.Tags().MatchesExpr(e, expr), slices.Contains(result, e); got != want {
+				t.Errorf("%q: MatchesExpr(%v) = %v, but QueryExpr says %v", src, e, got, want)
+			}
+		}
+	}
+}
+
+func TestQueryDoesNotMutateIndex(t *testing.T) {
+	world, _ := queryWorld()
+
+	world.Tags().Query("enemy & !boss")
+	world.Tags().Query("enemy | pickup")
+
+	if n := len(world.Tags().GetEntitiesByTag("enemy")); n != 3 {
+		t.Errorf("Expected 3 enemies after queries, got %d", n)
+	}
+}
@@ -1,11 +1,29 @@
 package entity
 
+import (
+	"slices"
+	"strings"
+)
+
+// TagSeparator splits hierarchical tags such as "enemy.flying.bat".
+const TagSeparator = '.'
+
 // TagManager handles entity tags with O(1) lookup via index.
+//
+// Tags are hierarchical: an entity tagged "enemy.flying.bat" also matches
+// "enemy.flying" and "enemy" in HasTag, GetEntitiesByTag and tag queries.
+// The reverse index stores every ancestor of each tag to keep lookups O(1).
 type TagManager struct {
-	entityTags map[Entity]map[string]bool // entity -> set of tags
-	tagIndex   map[string]map[Entity]bool // tag -> set of entities (reverse index)
+	entityTags map[Entity]map[string]bool // entity -> set of tags (as added)
+	tagIndex   map[string]map[Entity]bool // tag or ancestor -> set of entities (reverse index)
+	onAdded    []TagHook
+	onRemoved  []TagHook
 }
 
+// TagHook is called after a tag was added to or removed from an entity.
+// The tag is passed in its normalized form.
+type TagHook func(e Entity, tag string)
+
 // NewTagManager creates a new TagManager.
 func NewTagManager() *TagManager {
 	return &TagManager{
@@ -34,11 +52,17 @@ func (tm *TagManager) AddTag(e Entity, tag string) {
 	// Add to entity's tags
 	tm.entityTags[e][tag] = true
 
-	// Update reverse index
-	if tm.tagIndex[tag] == nil {
-		tm.tagIndex[tag] = make(map[Entity]bool)
+	// Update reverse index for the tag and all its ancestors
+	forEachTagPrefix(tag, func(prefix string) {
+		if tm.tagIndex[prefix] == nil {
+			tm.tagIndex[prefix] = make(map[Entity]bool)
+		}
+		tm.tagIndex[prefix][e] = true
+	})
+
+	for _, fn := range tm.onAdded {
+		fn(e, tag)
 	}
-	tm.tagIndex[tag][e] = true
 }
 
 // RemoveTag removes a tag from an entity.
@@ -48,22 +72,60 @@ func (tm *TagManager) RemoveTag(e Entity, tag string) {
 		return
 	}
 
+	if !tm.entityTags[e][tag] {
+		return
+	}
+
 	// Remove from entity's tags
 	delete(tm.entityTags[e], tag)
 
-	// Remove from reverse index
-	if tm.tagIndex[tag] != nil {
-		delete(tm.tagIndex[tag], e)
+	// Remove from reverse index, keeping ancestors still covered by another tag
+	forEachTagPrefix(tag, func(prefix string) {
+		if !tm.coversTag(e, prefix) {
+			delete(tm.tagIndex[prefix], e)
+		}
+	})
+
+	for _, fn := range tm.onRemoved {
+		fn(e, tag)
 	}
 }
 
-// HasTag checks if an entity has a specific tag.
+// OnTagAdded registers a hook called after AddTag adds a new tag.
+func (tm *TagManager) OnTagAdded(fn TagHook) {
+	tm.onAdded = append(tm.onAdded, fn)
+}
+
+// OnTagRemoved registers a hook called after a tag is removed, including
+// when RemoveAllTags runs during Cleanup.
+func (tm *TagManager) OnTagRemoved(fn TagHook) {
+	tm.onRemoved = append(tm.onRemoved, fn)
+}
+
+// coversTag reports whether any of e's own tags is prefix or a descendant of it.
+func (tm *TagManager) coversTag(e Entity, prefix string) bool {
+	for tag := range tm.entityTags[e] {
+		if isTagWithin(tag, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag checks if an entity has a specific tag or one of its descendants.
 func (tm *TagManager) HasTag(e Entity, tag string) bool {
+	tag = filterTag(tag)
+	return tm.tagIndex[tag][e]
+}
+
+// HasExactTag checks if an entity has exactly this tag, ignoring descendants.
+func (tm *TagManager) HasExactTag(e Entity, tag string) bool {
 	tag = filterTag(tag)
 	return tm.entityTags[e][tag]
 }
 
-// GetTags returns all tags for an entity.
+// GetTags returns the tags added to an entity (without implied ancestors),
+// sorted alphabetically.
 func (tm *TagManager) GetTags(e Entity) []string {
 	tags := tm.entityTags[e]
 	if tags == nil {
@@ -73,10 +135,13 @@ func (tm *TagManager) GetTags(e Entity) []string {
 	for tag := range tags {
 		result = append(result, tag)
 	}
+	slices.Sort(result)
 	return result
 }
 
-// GetEntitiesByTag returns all entities with the specified tag (O(1) lookup).
+// GetEntitiesByTag returns all entities with the specified tag or one of its
+// descendants (O(1) lookup), sorted by entity ID so callers iterate in the
+// same order on every run.
 func (tm *TagManager) GetEntitiesByTag(tag string) []Entity {
 	tag = filterTag(tag)
 	entities := tm.tagIndex[tag]
@@ -87,9 +152,15 @@ func (tm *TagManager) GetEntitiesByTag(tag string) []Entity {
 	for e := range entities {
 		result = append(result, e)
 	}
+	slices.Sort(result)
 	return result
 }
 
+// CountByTag returns how many entities have the specified tag without allocating.
+func (tm *TagManager) CountByTag(tag string) int {
+	return len(tm.tagIndex[filterTag(tag)])
+}
+
 // RemoveAllTags removes all tags from an entity. Called during Cleanup.
 func (tm *TagManager) RemoveAllTags(e Entity) {
 	tags := tm.entityTags[e]
@@ -98,15 +169,45 @@ func (tm *TagManager) RemoveAllTags(e Entity) {
 	}
 	// Remove from all reverse indexes
 	for tag := range tags {
-		if tm.tagIndex[tag] != nil {
-			delete(tm.tagIndex[tag], e)
-		}
+		forEachTagPrefix(tag, func(prefix string) {
+			delete(tm.tagIndex[prefix], e)
+		})
 	}
 	// Remove entity's tag set
 	delete(tm.entityTags, e)
+
+	if len(tm.onRemoved) > 0 {
+		removed := make([]string, 0, len(tags))
+		for tag := range tags {
+			removed = append(removed, tag)
+		}
+		slices.Sort(removed)
+		for _, tag := range removed {
+			for _, fn := range tm.onRemoved {
+				fn(e, tag)
+			}
+		}
+	}
 }
 
-// filterTag normalizes: lowercase, keeps only a-z, 0-9, underscore
+// forEachTagPrefix calls fn for "a", "a.b" and "a.b.c" when tag is "a.b.c".
+func forEachTagPrefix(tag string, fn func(prefix string)) {
+	for i := 0; i < len(tag); i++ {
+		if tag[i] == TagSeparator {
+			fn(tag[:i])
+		}
+	}
+	fn(tag)
+}
+
+// isTagWithin reports whether tag equals ancestor or is one of its descendants.
+func isTagWithin(tag, ancestor string) bool {
+	return tag == ancestor ||
+		(strings.HasPrefix(tag, ancestor) && tag[len(ancestor)] == TagSeparator)
+}
+
+// filterTag normalizes: lowercase, keeps only a-z, 0-9, underscore and the
+// separator. Empty path segments are dropped, so "enemy..bat." becomes "enemy.bat".
 func filterTag(s string) string {
 	result := make([]byte, 0, len(s))
 	for i := 0; i < len(s); i++ {
@@ -121,6 +222,14 @@ func filterTag(s string) string {
 		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' {
 			result = append(result, c)
 		}
+
+		// Keep separators only between non-empty segments
+		if c == TagSeparator && len(result) > 0 && result[len(result)-1] != TagSeparator {
+			result = append(result, c)
+		}
+	}
+	if n := len(result); n > 0 && result[n-1] == TagSeparator {
+		result = result[:n-1]
 	}
 	return string(result)
 }
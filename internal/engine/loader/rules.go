@@ -0,0 +1,366 @@
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/actions"
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/conditions"
+	"github.com/GiannisPettas/ember2D/internal/engine/registry"
+	jsonutil "github.com/GiannisPettas/ember2D/internal/util/json"
+)
+
+// RuleFile is the on-disk form of a set of rule cards (rules.json).
+//
+// Example:
+//
+//	{
+//	  "rules": [
+//	    {
+//	      "id": "player_hit",
+//	      "trigger": {"type": "collision", "a": "player", "b": "enemy", "any_order": true},
+//	      "conditions": [{"type": "always_true"}],
+//	      "actions": [{"type": "debug_log", "params": {"message": "Player hit!"}}]
+//	    },
+//	    {
+//	      "id": "spawn_wave",
+//	      "trigger": {"type": "loop", "count": 5},
+//	      "actions": [{"type": "debug_log", "params": {"message": "spawn"}}]
+//	    }
+//	  ]
+//	}
+type RuleFile struct {
+	Rules []RuleSpec `json:"rules"`
+}
+
+// RuleSpec is a single rule card: trigger + conditions + actions.
+type RuleSpec struct {
+	ID         string      `json:"id"`
+	Priority   int         `json:"priority,omitempty"` // higher runs first
+	Group      string      `json:"group,omitempty"`
+	Disabled   bool        `json:"disabled,omitempty"`
+	Trigger    TriggerSpec `json:"trigger"`
+	Conditions []BlockSpec `json:"conditions,omitempty"`
+	Actions    []BlockSpec `json:"actions"`
+}
+
+// TriggerSpec mirrors behavior.Trigger.
+type TriggerSpec struct {
+	Type     string     `json:"type"`
+	Entities []string   `json:"entities,omitempty"`
+	A        string     `json:"a,omitempty"`
+	B        string     `json:"b,omitempty"`
+	AnyOrder bool       `json:"any_order,omitempty"`
+	Interval int        `json:"interval,omitempty"`
+	Delay    int        `json:"delay,omitempty"`
+	Repeat   int        `json:"repeat,omitempty"`
+	Ticks    bool       `json:"ticks,omitempty"`
+	Count    int        `json:"count,omitempty"`
+	Until    *BlockSpec `json:"until,omitempty"`
+}
+
+// BlockSpec names a registered condition or action and its parameters.
+type BlockSpec struct {
+	Type   string         `json:"type"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// RuleError reports an invalid rule with its file, rule and field path, e.g.
+//
+//	rules.json: rule "player_hit": actions[0].params.message: expected string, got float64
+//
+// Errors found while decoding the file, such as a misspelled field, also
+// carry the line and column:
+//
+//	rules.json:14:9: rule "wave_timer": trigger: unknown field "intervall"
+type RuleError struct {
+	File      string // empty when compiling from a reader
+	Line, Col int    // zero unless the error was found while decoding
+	Index     int    // position of the rule in the file
+	RuleID    string // empty if the rule has no id
+	Path      string // field path inside the rule, e.g. "actions[0].params.message"
+	Err       error
+}
+
+func (e *RuleError) Error() string {
+	msg := e.File
+	if e.Line > 0 {
+		if msg != "" {
+			msg += ":"
+		}
+		msg += fmt.Sprintf("%d:%d", e.Line, e.Col)
+	}
+	if msg != "" {
+		msg += ": "
+	}
+	if e.RuleID != "" {
+		msg += fmt.Sprintf("rule %q: ", e.RuleID)
+	} else {
+		msg += fmt.Sprintf("rules[%d]: ", e.Index)
+	}
+	if e.Path != "" {
+		msg += e.Path + ": "
+	}
+	return msg + e.Err.Error()
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// DefaultRegistry returns a registry with all built-in conditions and actions.
+func DefaultRegistry() *registry.Registry {
+	r := registry.New()
+	conditions.Register(r)
+	actions.Register(r)
+	return r
+}
+
+// CompileRules decodes a rule file from r and compiles it into behaviors.
+func CompileRules(r io.Reader, reg *registry.Registry) ([]*behavior.Behavior, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	file, err := decodeRules(data, "")
+	if err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+	return file.Compile(reg, "")
+}
+
+// LoadRulesFile is CompileRules for a file on disk.
+func LoadRulesFile(path string, reg *registry.Registry) ([]*behavior.Behavior, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := decodeRules(data, path)
+	if err != nil {
+		return nil, err
+	}
+	return file.Compile(reg, path)
+}
+
+// decodeRules strictly decodes a rule file. Errors inside a rule become a
+// *RuleError naming the rule, so a misspelled field reads like a compile
+// error; other errors are a *jsonutil.Error.
+func decodeRules(data []byte, fileName string) (*RuleFile, error) {
+	var file RuleFile
+	err := jsonutil.UnmarshalStrict(data, &file)
+	if err == nil {
+		return &file, nil
+	}
+
+	var jsonErr *jsonutil.Error
+	if !errors.As(err, &jsonErr) {
+		return nil, err
+	}
+	index, rest, ok := splitRulePath(jsonErr.Path)
+	if !ok {
+		jsonErr.File = fileName
+		return nil, jsonErr
+	}
+
+	// The strict decode stopped at the error; a lenient one still finds the
+	// rule's id for the message.
+	var ids struct {
+		Rules []struct {
+			ID string `json:"id"`
+		} `json:"rules"`
+	}
+	json.Unmarshal(data, &ids)
+	re := &RuleError{File: fileName, Line: jsonErr.Line, Col: jsonErr.Col, Index: index, Path: rest, Err: jsonErr.Err}
+	if index < len(ids.Rules) {
+		re.RuleID = ids.Rules[index].ID
+	}
+	return nil, re
+}
+
+// splitRulePath splits "rules[3].trigger.interval" into 3 and
+// "trigger.interval".
+func splitRulePath(p string) (index int, rest string, ok bool) {
+	p, ok = strings.CutPrefix(p, "rules[")
+	if !ok {
+		return 0, "", false
+	}
+	n, rest, ok := strings.Cut(p, "]")
+	if !ok {
+		return 0, "", false
+	}
+	index, err := strconv.Atoi(n)
+	if err != nil {
+		return 0, "", false
+	}
+	return index, strings.TrimPrefix(rest, "."), true
+}
+
+// Compile resolves every condition and action through reg and returns the
+// behaviors in file order. fileName is only used in error messages.
+func (f *RuleFile) Compile(reg *registry.Registry, fileName string) ([]*behavior.Behavior, error) {
+	behaviors := make([]*behavior.Behavior, 0, len(f.Rules))
+	seen := make(map[string]bool, len(f.Rules))
+
+	for i, rule := range f.Rules {
+		fail := func(path string, err error) error {
+			return &RuleError{File: fileName, Index: i, RuleID: rule.ID, Path: path, Err: err}
+		}
+
+		if rule.ID == "" {
+			return nil, fail("id", errors.New("required"))
+		}
+		if seen[rule.ID] {
+			return nil, fail("id", errors.New("duplicate rule id"))
+		}
+		seen[rule.ID] = true
+
+		b, err := compileRule(rule, reg)
+		if err != nil {
+			var re *RuleError
+			if errors.As(err, &re) {
+				return nil, fail(re.Path, re.Err)
+			}
+			return nil, fail("", err)
+		}
+		behaviors = append(behaviors, b)
+	}
+	return behaviors, nil
+}
+
+// compileRule builds one behavior. Errors are *RuleError carrying only Path.
+func compileRule(rule RuleSpec, reg *registry.Registry) (*behavior.Behavior, error) {
+	if rule.Trigger.Type == "" {
+		return nil, &RuleError{Path: "trigger.type", Err: errors.New("required")}
+	}
+	if err := checkTrigger(rule.Trigger); err != nil {
+		return nil, err
+	}
+	if len(rule.Actions) == 0 {
+		return nil, &RuleError{Path: "actions", Err: errors.New("at least one action is required")}
+	}
+
+	b := &behavior.Behavior{
+		ID:       rule.ID,
+		Priority: rule.Priority,
+		Group:    rule.Group,
+		Disabled: rule.Disabled,
+		Trigger: behavior.Trigger{
+			Type:     rule.Trigger.Type,
+			Entities: rule.Trigger.Entities,
+			A:        rule.Trigger.A,
+			B:        rule.Trigger.B,
+			AnyOrder: rule.Trigger.AnyOrder,
+			Interval: rule.Trigger.Interval,
+			Delay:    rule.Trigger.Delay,
+			Repeat:   rule.Trigger.Repeat,
+			Ticks:    rule.Trigger.Ticks,
+			Count:    rule.Trigger.Count,
+		},
+	}
+
+	if spec := rule.Trigger.Until; spec != nil {
+		if spec.Type == "" {
+			return nil, &RuleError{Path: "trigger.until.type", Err: errors.New("required")}
+		}
+		cond, err := reg.NewCondition(spec.Type, spec.Params)
+		if err != nil {
+			return nil, blockError("trigger.until", err)
+		}
+		b.Trigger.Until = cond
+	}
+
+	for i, spec := range rule.Conditions {
+		path := fmt.Sprintf("conditions[%d]", i)
+		if spec.Type == "" {
+			return nil, &RuleError{Path: path + ".type", Err: errors.New("required")}
+		}
+		cond, err := reg.NewCondition(spec.Type, spec.Params)
+		if err != nil {
+			return nil, blockError(path, err)
+		}
+		b.Conditions = append(b.Conditions, cond)
+	}
+
+	for i, spec := range rule.Actions {
+		path := fmt.Sprintf("actions[%d]", i)
+		if spec.Type == "" {
+			return nil, &RuleError{Path: path + ".type", Err: errors.New("required")}
+		}
+		act, err := reg.NewAction(spec.Type, spec.Params)
+		if err != nil {
+			return nil, blockError(path, err)
+		}
+		b.Actions = append(b.Actions, act)
+	}
+	return b, nil
+}
+
+// checkTrigger validates the participant patterns and the timer and loop
+// fields of a trigger, which are only allowed on their own trigger type.
+func checkTrigger(t TriggerSpec) error {
+	for i, p := range t.Entities {
+		if _, err := path.Match(p, ""); err != nil {
+			return &RuleError{Path: fmt.Sprintf("trigger.entities[%d]", i), Err: fmt.Errorf("bad pattern %q", p)}
+		}
+	}
+	for _, f := range []struct{ name, pattern string }{{"a", t.A}, {"b", t.B}} {
+		if _, err := path.Match(f.pattern, ""); err != nil {
+			return &RuleError{Path: "trigger." + f.name, Err: fmt.Errorf("bad pattern %q", f.pattern)}
+		}
+	}
+
+	for _, f := range []struct {
+		name  string
+		value int
+	}{{"interval", t.Interval}, {"delay", t.Delay}, {"repeat", t.Repeat}, {"count", t.Count}} {
+		if f.value < 0 {
+			return &RuleError{Path: "trigger." + f.name, Err: errors.New("must not be negative")}
+		}
+	}
+	// Timer and loop fields would be silently ignored on other triggers.
+	for _, f := range []struct {
+		name  string
+		set   bool
+		owner string
+	}{
+		{"interval", t.Interval != 0, behavior.TriggerTimer},
+		{"delay", t.Delay != 0, behavior.TriggerTimer},
+		{"repeat", t.Repeat != 0, behavior.TriggerTimer},
+		{"ticks", t.Ticks, behavior.TriggerTimer},
+		{"count", t.Count != 0, behavior.TriggerLoop},
+		{"until", t.Until != nil, behavior.TriggerLoop},
+	} {
+		if f.set && t.Type != f.owner {
+			return &RuleError{Path: "trigger." + f.name, Err: fmt.Errorf("only applies to %s triggers", f.owner)}
+		}
+	}
+	if t.Type == behavior.TriggerLoop && t.Count == 0 && t.Until == nil {
+		return &RuleError{Path: "trigger.count", Err: errors.New("loop needs a count or an until condition")}
+	}
+	if t.Type == behavior.TriggerTimer && t.Interval == 0 && t.Repeat > 1 {
+		// Without an interval a timer fires once, so a larger repeat would be
+		// silently ignored.
+		return &RuleError{Path: "trigger.repeat", Err: errors.New("needs an interval to fire more than once")}
+	}
+	return nil
+}
+
+// blockError extends path with the offending field, if known.
+func blockError(path string, err error) error {
+	var pe *registry.ParamError
+	var ue *registry.UnknownError
+	switch {
+	case errors.As(err, &pe):
+		return &RuleError{Path: path + ".params." + pe.Param, Err: pe.Err}
+	case errors.As(err, &ue):
+		return &RuleError{Path: path + ".type", Err: err}
+	}
+	return &RuleError{Path: path, Err: err}
+}
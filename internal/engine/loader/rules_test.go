@@ -0,0 +1,272 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/actions"
+	"github.com/GiannisPettas/ember2D/internal/engine/conditions"
+)
+
+// ============================================
+// Compile Tests
+// ============================================
+
+func TestCompileRules(t *testing.T) {
+	input := `{
+		"rules": [
+			{
+				"id": "player_hit",
+				"trigger": {"type": "collision", "entities": ["player", "enemy"], "a": "player", "b": "enemy.*", "any_order": true},
+				"conditions": [{"type": "always_true"}],
+				"actions": [{"type": "debug_log", "params": {"message": "Player hit!"}}]
+			},
+			{
+				"id": "spawner",
+				"priority": 10,
+				"trigger": {"type": "timer", "interval": 2000},
+				"actions": [{"type": "debug_log"}]
+			},
+			{
+				"id": "wave",
+				"group": "boss_phase_2",
+				"disabled": true,
+				"trigger": {"type": "loop", "count": 3, "until": {"type": "always_true"}},
+				"actions": [{"type": "debug_log"}]
+			}
+		]
+	}`
+
+	behaviors, err := CompileRules(strings.NewReader(input), DefaultRegistry())
+	if err != nil {
+		t.Fatalf("CompileRules failed: %v", err)
+	}
+	if len(behaviors) != 3 {
+		t.Fatalf("Expected 3 behaviors, got %d", len(behaviors))
+	}
+
+	hit := behaviors[0]
+	if hit.ID != "player_hit" || hit.Trigger.Type != "collision" || len(hit.Trigger.Entities) != 2 {
+		t.Errorf("Unexpected behavior %+v", hit)
+	}
+	if hit.Trigger.A != "player" || hit.Trigger.B != "enemy.*" || !hit.Trigger.AnyOrder {
+		t.Errorf("Expected player/enemy.* pair in any order, got %+v", hit.Trigger)
+	}
+	if _, ok := hit.Conditions[0].(*conditions.AlwaysTrue); !ok {
+		t.Errorf("Expected AlwaysTrue, got %T", hit.Conditions[0])
+	}
+	if log, ok := hit.Actions[0].(*actions.DebugLog); !ok || log.Message != "Player hit!" {
+		t.Errorf("Expected DebugLog with message, got %#v", hit.Actions[0])
+	}
+	if behaviors[1].Priority != 10 {
+		t.Errorf("Expected priority 10, got %d", behaviors[1].Priority)
+	}
+	if behaviors[1].Trigger.Interval != 2000 {
+		t.Errorf("Expected interval 2000, got %d", behaviors[1].Trigger.Interval)
+	}
+	if behaviors[2].Group != "boss_phase_2" || !behaviors[2].Disabled {
+		t.Errorf("Expected disabled behavior in group boss_phase_2, got %+v", behaviors[2])
+	}
+	wave := behaviors[2].Trigger
+	if _, ok := wave.Until.(*conditions.AlwaysTrue); !ok || wave.Count != 3 {
+		t.Errorf("Expected loop with count 3 and an until condition, got %+v", wave)
+	}
+}
+
+func TestCompileOneShotTimer(t *testing.T) {
+	input := `{"rules": [{"id": "once", "trigger": {"type": "timer", "delay": 500}, "actions": [{"type": "debug_log"}]}]}`
+
+	behaviors, err := CompileRules(strings.NewReader(input), DefaultRegistry())
+	if err != nil {
+		t.Fatalf("A timer with only a delay should compile: %v", err)
+	}
+	if tr := behaviors[0].Trigger; tr.Delay != 500 || tr.Interval != 0 {
+		t.Errorf("Unexpected trigger %+v", tr)
+	}
+}
+
+func TestCompileConditionCombinators(t *testing.T) {
+	input := `{"rules": [{
+		"id": "hurt",
+		"trigger": {"type": "hit"},
+		"conditions": [{"type": "any", "params": {"conditions": [
+			{"type": "compare", "params": {"expr": "event.damage >= 10"}},
+			{"type": "not", "params": {"condition": {"type": "always_true"}}}
+		]}}],
+		"actions": [{"type": "debug_log"}]
+	}]}`
+
+	behaviors, err := CompileRules(strings.NewReader(input), DefaultRegistry())
+	if err != nil {
+		t.Fatalf("CompileRules failed: %v", err)
+	}
+	anyCond, ok := behaviors[0].Conditions[0].(*conditions.Any)
+	if !ok || len(anyCond.Conditions) != 2 {
+		t.Fatalf("Expected Any with 2 conditions, got %#v", behaviors[0].Conditions[0])
+	}
+	if _, ok := anyCond.Conditions[0].(*conditions.Compare); !ok {
+		t.Errorf("Expected Compare, got %T", anyCond.Conditions[0])
+	}
+	if not, ok := anyCond.Conditions[1].(*conditions.Not); !ok || not.Condition == nil {
+		t.Errorf("Expected Not with a condition, got %#v", anyCond.Conditions[1])
+	}
+}
+
+// ============================================
+// Error Tests
+// ============================================
+
+func TestCompileRulesErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"missing id",
+			`{"rules": [{"trigger": {"type": "start"}, "actions": [{"type": "debug_log"}]}]}`,
+			`rules[0]: id: required`,
+		},
+		{
+			"duplicate id",
+			`{"rules": [
+				{"id": "a", "trigger": {"type": "start"}, "actions": [{"type": "debug_log"}]},
+				{"id": "a", "trigger": {"type": "start"}, "actions": [{"type": "debug_log"}]}
+			]}`,
+			`rule "a": id: duplicate rule id`,
+		},
+		{
+			"missing trigger type",
+			`{"rules": [{"id": "a", "trigger": {}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.type: required`,
+		},
+		{
+			"no actions",
+			`{"rules": [{"id": "a", "trigger": {"type": "start"}}]}`,
+			`rule "a": actions: at least one action is required`,
+		},
+		{
+			"bad nested condition",
+			`{"rules": [{"id": "a", "trigger": {"type": "hit"}, "conditions": [{"type": "any", "params": {"conditions": [{"type": "always_true"}, {"type": "compare", "params": {"expr": "event.damage"}}]}}], "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": conditions[0].params.conditions[1].params.expr: no comparison operator in "event.damage"`,
+		},
+		{
+			"bad pattern",
+			`{"rules": [{"id": "a", "trigger": {"type": "collision", "b": "enemy["}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.b: bad pattern "enemy["`,
+		},
+		{
+			"negative delay",
+			`{"rules": [{"id": "a", "trigger": {"type": "timer", "interval": 100, "delay": -1}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.delay: must not be negative`,
+		},
+		{
+			"unbounded loop",
+			`{"rules": [{"id": "a", "trigger": {"type": "loop"}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.count: loop needs a count or an until condition`,
+		},
+		{
+			"until on a non-loop trigger",
+			`{"rules": [{"id": "a", "trigger": {"type": "hit", "until": {"type": "always_true"}}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.until: only applies to loop triggers`,
+		},
+		{
+			"interval on a non-timer trigger",
+			`{"rules": [{"id": "a", "trigger": {"type": "loop", "count": 3, "interval": 100}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.interval: only applies to timer triggers`,
+		},
+		{
+			"ticks on a non-timer trigger",
+			`{"rules": [{"id": "a", "trigger": {"type": "start", "ticks": true}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.ticks: only applies to timer triggers`,
+		},
+		{
+			"unknown until condition",
+			`{"rules": [{"id": "a", "trigger": {"type": "loop", "until": {"type": "nope"}}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.until.type: unknown condition "nope"`,
+		},
+		{
+			"repeating timer without interval",
+			`{"rules": [{"id": "a", "trigger": {"type": "timer", "delay": 500, "repeat": 3}, "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": trigger.repeat: needs an interval to fire more than once`,
+		},
+		{
+			"unknown condition",
+			`{"rules": [{"id": "a", "trigger": {"type": "start"}, "conditions": [{"type": "nope"}], "actions": [{"type": "debug_log"}]}]}`,
+			`rule "a": conditions[0].type: unknown condition "nope"`,
+		},
+		{
+			"unknown action",
+			`{"rules": [{"id": "a", "trigger": {"type": "start"}, "actions": [{"type": "debug_log"}, {"type": "explode"}]}]}`,
+			`rule "a": actions[1].type: unknown action "explode"`,
+		},
+		{
+			"bad parameter",
+			`{"rules": [{"id": "a", "trigger": {"type": "start"}, "actions": [{"type": "debug_log", "params": {"message": 5}}]}]}`,
+			`rule "a": actions[0].params.message: expected string, got float64`,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := CompileRules(strings.NewReader(tc.input), DefaultRegistry())
+		if err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+			continue
+		}
+		if err.Error() != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, err.Error())
+		}
+		var re *RuleError
+		if !errors.As(err, &re) {
+			t.Errorf("%s: expected a *RuleError, got %T", tc.name, err)
+		}
+	}
+}
+
+func TestCompileRulesRejectsUnknownFields(t *testing.T) {
+	cases := map[string]string{
+		`{"rules": [{"id": "a", "trigger": {"type": "start", "intervall": 5}, "actions": [{"type": "debug_log"}]}]}`:     `rules: 1:53: rule "a": trigger: unknown field "intervall"`,
+		`{"rules": [{"id": "a", "trigger": {"type": "start"}, "action": [{"type": "debug_log"}]}]}`:                      `rules: 1:54: rule "a": unknown field "action"`,
+		`{"rules": [{"trigger": {"type": "start"}, "actions": [{"type": "debug_log", "parms": {}}]}]}`:                   `rules: 1:77: rules[0]: actions[0]: unknown field "parms"`,
+		`{"rules": [{"id": "a", "trigger": {"type": "start"}, "actions": [{"type": "debug_log"}]}], "extra": true}`:      `rules: 1:92: unknown field "extra"`,
+		`{"rules": [{"id": "a", "priority": "high", "trigger": {"type": "start"}, "actions": [{"type": "debug_log"}]}]}`: `rules: 1:42: rule "a": priority: expected int, got string`,
+	}
+	for input, want := range cases {
+		_, err := CompileRules(strings.NewReader(input), DefaultRegistry())
+		if err == nil || err.Error() != want {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+}
+
+func TestLoadRulesFileUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	data := `{
+  "rules": [
+    {"id": "a", "trigger": {"type": "start"}, "actions": [{"type": "debug_log"}]},
+    {
+      "id": "wave_timer",
+      "trigger": {"type": "timer", "intervall": 500},
+      "actions": [{"type": "debug_log"}]
+    }
+  ]
+}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadRulesFile(path, DefaultRegistry())
+	var re *RuleError
+	if !errors.As(err, &re) {
+		t.Fatalf("Expected a *RuleError, got %v", err)
+	}
+	if re.File != path || re.Index != 1 || re.RuleID != "wave_timer" || re.Path != "trigger" || re.Line != 6 {
+		t.Errorf("Unexpected error fields: %+v", *re)
+	}
+	if want := path + `:6:36: rule "wave_timer": trigger: unknown field "intervall"`; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
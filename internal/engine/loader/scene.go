@@ -0,0 +1,209 @@
+// Package loader turns ember2D's JSON files into runtime objects:
+// level.json scenes into World entities and components, and rules.json rule
+// cards into behaviors.
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+	jsonutil "github.com/GiannisPettas/ember2D/internal/util/json"
+)
+
+// Scene is the on-disk form of a World (level.json).
+//
+// Example:
+//
+//	{
+//	  "entities": [
+//	    {
+//	      "id": 0,
+//	      "tags": ["player"],
+//	      "components": {
+//	        "position": {"x": 300, "y": 220},
+//	        "display":  {"width": 30, "height": 30, "r": 50, "g": 100, "b": 255}
+//	      }
+//	    }
+//	  ],
+//	  "prefabs": {
+//	    "bullet": {"tags": ["projectile"], "components": {"position": {"x": 0, "y": 0}}}
+//	  }
+//	}
+type Scene struct {
+	Entities []SceneEntity `json:"entities"`
+
+	// Prefabs are entity templates for spawn_entity, keyed by name.
+	Prefabs map[string]entity.Prefab `json:"prefabs,omitempty"`
+}
+
+// SceneEntity is one entity in a scene file.
+// ID only identifies the entity within the file; loading creates new
+// entities and returns how file IDs map to them.
+// Components are keyed by the name they were registered with in the World.
+type SceneEntity struct {
+	ID         uint64                     `json:"id"`
+	Tags       []string                   `json:"tags,omitempty"`
+	Components map[string]json.RawMessage `json:"components,omitempty"`
+}
+
+// LoadScene decodes a scene from r and instantiates it into world.
+// It returns the mapping from file IDs to the created entities.
+func LoadScene(r io.Reader, world *entity.World) (map[uint64]entity.Entity, error) {
+	var scene Scene
+	if err := jsonutil.DecodeStrict(r, &scene); err != nil {
+		return nil, fmt.Errorf("scene: %w", err)
+	}
+	return scene.Instantiate(world)
+}
+
+// LoadSceneFile is LoadScene for a file on disk.
+func LoadSceneFile(path string, world *entity.World) (map[uint64]entity.Entity, error) {
+	var scene Scene
+	if err := jsonutil.DecodeFile(path, &scene); err != nil {
+		return nil, err
+	}
+	ids, err := scene.Instantiate(world)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ids, nil
+}
+
+// Instantiate creates the scene's entities, tags and components in world,
+// then defines its prefabs. IDs and component names are validated first, so
+// a scene referencing an unknown component leaves the world untouched. If a
+// component fails to decode, the entities created so far are destroyed again
+// (like World.Spawn does) and no prefabs are defined.
+func (s *Scene) Instantiate(world *entity.World) (map[uint64]entity.Entity, error) {
+	if err := s.validate(world); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uint64]entity.Entity, len(s.Entities))
+	for i, se := range s.Entities {
+		e := world.CreateEntity(se.Tags...)
+		ids[se.ID] = e
+		names := make([]string, 0, len(se.Components))
+		for name := range se.Components {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			store := world.Component(name).(entity.ComponentCodec)
+			if err := store.UnmarshalComponent(e, se.Components[name]); err != nil {
+				for _, created := range ids {
+					world.DestroyEntity(created)
+				}
+				return nil, fmt.Errorf("entities[%d].components.%s: %w", i, name, err)
+			}
+		}
+	}
+
+	for name, p := range s.Prefabs {
+		world.DefinePrefab(name, p)
+	}
+	return ids, nil
+}
+
+// validate checks IDs and component names before anything is created.
+func (s *Scene) validate(world *entity.World) error {
+	seen := make(map[uint64]bool, len(s.Entities))
+	for i, se := range s.Entities {
+		if seen[se.ID] {
+			return fmt.Errorf("entities[%d].id: duplicate id %d", i, se.ID)
+		}
+		seen[se.ID] = true
+
+		for name := range se.Components {
+			if err := checkComponent(world, name); err != nil {
+				return fmt.Errorf("entities[%d].components.%s: %w", i, name, err)
+			}
+		}
+	}
+
+	for name, p := range s.Prefabs {
+		for comp := range p.Components {
+			if err := checkComponent(world, comp); err != nil {
+				return fmt.Errorf("prefabs.%s.components.%s: %w", name, comp, err)
+			}
+		}
+	}
+	return nil
+}
+
+func checkComponent(world *entity.World, name string) error {
+	store := world.Component(name)
+	if store == nil {
+		return errors.New("unknown component")
+	}
+	if _, ok := store.(entity.ComponentCodec); !ok {
+		return errors.New("component cannot be loaded from JSON")
+	}
+	return nil
+}
+
+// CaptureScene snapshots the world's prefabs and every alive entity in world,
+// with its tags and all registered components, in entity ID order. File IDs
+// are assigned 0..n-1 in that order, so saving a freshly loaded scene
+// reproduces the same file.
+func CaptureScene(world *entity.World) (*Scene, error) {
+	names := world.ComponentNames()
+	scene := &Scene{Entities: make([]SceneEntity, 0, world.EntityCount())}
+
+	for i, e := range world.Entities() {
+		se := SceneEntity{
+			ID:   uint64(i),
+			Tags: world.Tags().GetTags(e),
+		}
+		for _, name := range names {
+			store, ok := world.Component(name).(entity.ComponentCodec)
+			if !ok || !store.Has(e) {
+				continue
+			}
+			data, err := store.MarshalComponent(e)
+			if err != nil {
+				return nil, fmt.Errorf("entity %d component %s: %w", e, name, err)
+			}
+			if se.Components == nil {
+				se.Components = make(map[string]json.RawMessage)
+			}
+			se.Components[name] = data
+		}
+		scene.Entities = append(scene.Entities, se)
+	}
+
+	for _, name := range world.PrefabNames() {
+		if scene.Prefabs == nil {
+			scene.Prefabs = make(map[string]entity.Prefab)
+		}
+		scene.Prefabs[name], _ = world.Prefab(name)
+	}
+	return scene, nil
+}
+
+// SaveScene captures world and writes it to w as indented JSON.
+func SaveScene(w io.Writer, world *entity.World) error {
+	scene, err := CaptureScene(world)
+	if err != nil {
+		return fmt.Errorf("scene: %w", err)
+	}
+	return jsonutil.EncodeIndent(w, scene)
+}
+
+// SaveSceneFile is SaveScene for a file on disk.
+func SaveSceneFile(path string, world *entity.World) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := SaveScene(f, world); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return f.Close()
+}
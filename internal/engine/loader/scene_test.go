@@ -0,0 +1,188 @@
+package loader
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/components"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+)
+
+// Test component types
+type Health struct {
+	Current int `json:"current"`
+	Max     int `json:"max"`
+}
+
+// sceneWorld returns a world with the component types used in these tests.
+func sceneWorld() (*entity.World, *components.ComponentManager[components.Position], *components.ComponentManager[Health]) {
+	world := entity.NewWorld()
+	positions := components.Register[components.Position](world, "position")
+	healths := components.RegisterDense[Health](world, "health")
+	return world, positions, healths
+}
+
+// ============================================
+// Round-trip Tests
+// ============================================
+
+func TestSceneRoundTrip(t *testing.T) {
+	world, positions, healths := sceneWorld()
+
+	player := world.CreateEntity("player")
+	positions.Add(player, components.Position{X: 300, Y: 220})
+	healths.Add(player, Health{Current: 80, Max: 100})
+
+	doomed := world.CreateEntity("enemy")
+	world.CreateEntity("enemy.flying", "boss")
+	world.DestroyEntity(doomed)
+	world.Cleanup()
+
+	var first bytes.Buffer
+	if err := SaveScene(&first, world); err != nil {
+		t.Fatalf("SaveScene failed: %v", err)
+	}
+
+	loaded, loadedPositions, loadedHealths := sceneWorld()
+	ids, err := LoadScene(bytes.NewReader(first.Bytes()), loaded)
+	if err != nil {
+		t.Fatalf("LoadScene failed: %v", err)
+	}
+
+	if loaded.EntityCount() != 2 {
+		t.Errorf("Expected 2 entities, got %d", loaded.EntityCount())
+	}
+	p := ids[0]
+	if pos := loadedPositions.Get(p); pos == nil || pos.X != 300 || pos.Y != 220 {
+		t.Errorf("Position not restored: %v", pos)
+	}
+	if hp := loadedHealths.Get(p); hp == nil || hp.Current != 80 {
+		t.Errorf("Health not restored: %v", hp)
+	}
+	if !loaded.Tags().HasTag(p, "player") {
+		t.Error("Tags not restored")
+	}
+	if n := len(loaded.Tags().GetEntitiesByTag("enemy")); n != 1 {
+		t.Errorf("Expected 1 enemy, got %d", n)
+	}
+
+	var second bytes.Buffer
+	if err := SaveScene(&second, loaded); err != nil {
+		t.Fatalf("SaveScene failed: %v", err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("Round trip changed the scene:\n%s\nvs\n%s", first.String(), second.String())
+	}
+}
+
+func TestScenePrefabs(t *testing.T) {
+	world, positions, _ := sceneWorld()
+	input := `{
+		"entities": [{"id": 0, "tags": ["player"]}],
+		"prefabs": {"Bullet": {"tags": ["projectile"], "components": {"position": {"x": 4, "y": 2}}}}
+	}`
+	if _, err := LoadScene(strings.NewReader(input), world); err != nil {
+		t.Fatalf("LoadScene failed: %v", err)
+	}
+
+	e, err := world.Spawn("bullet")
+	if err != nil {
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	if pos := positions.Get(e); pos == nil || pos.X != 4 || pos.Y != 2 {
+		t.Errorf("Prefab position not applied: %v", pos)
+	}
+	world.DestroyEntity(e)
+	world.Cleanup()
+
+	scene, err := CaptureScene(world)
+	if err != nil {
+		t.Fatalf("CaptureScene failed: %v", err)
+	}
+	if p, ok := scene.Prefabs["bullet"]; !ok || len(p.Tags) != 1 || p.Tags[0] != "projectile" {
+		t.Errorf("Prefab not captured: %+v", scene.Prefabs)
+	}
+}
+
+func TestSaveSceneIsDeterministic(t *testing.T) {
+	save := func() string {
+		world, positions, healths := sceneWorld()
+		for i := 0; i < 20; i++ {
+			e := world.CreateEntity("enemy", "ai")
+			positions.Add(e, components.Position{X: float64(i)})
+			healths.Add(e, Health{Current: i, Max: 20})
+		}
+		var buf bytes.Buffer
+		if err := SaveScene(&buf, world); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	first := save()
+	for i := 0; i < 10; i++ {
+		if save() != first {
+			t.Fatal("SaveScene output differs between runs")
+		}
+	}
+}
+
+// ============================================
+// Error Tests
+// ============================================
+
+func TestLoadSceneErrors(t *testing.T) {
+	cases := map[string]string{
+		"unknown component": `{"entities": [{"id": 1, "components": {"mana": {}}}]}`,
+		"duplicate id":      `{"entities": [{"id": 1}, {"id": 1}]}`,
+		"unknown field":     `{"entities": [{"id": 1, "components": {"position": {"z": 1}}}]}`,
+		"bad type":          `{"entities": [{"id": "one"}]}`,
+		"syntax":            `{"entities": [`,
+		"prefab component":  `{"entities": [], "prefabs": {"orb": {"components": {"mana": {}}}}}`,
+	}
+	for name, input := range cases {
+		world, _, _ := sceneWorld()
+		if _, err := LoadScene(strings.NewReader(input), world); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadSceneErrorLeavesWorldUntouched(t *testing.T) {
+	world, _, _ := sceneWorld()
+	input := `{"entities": [{"id": 1, "tags": ["player"]}, {"id": 2, "components": {"mana": {}}}]}`
+
+	_, err := LoadScene(strings.NewReader(input), world)
+	if err == nil || !strings.Contains(err.Error(), "entities[1].components.mana") {
+		t.Errorf("Expected error naming entities[1].components.mana, got %v", err)
+	}
+	if world.EntityCount() != 0 {
+		t.Errorf("Expected no entities after a failed load, got %d", world.EntityCount())
+	}
+}
+
+func TestLoadSceneDecodeErrorRollsBack(t *testing.T) {
+	world, _, _ := sceneWorld()
+	input := `{
+		"entities": [
+			{"id": 1, "tags": ["player"], "components": {"position": {"x": 1}}},
+			{"id": 2, "components": {"position": {"x": "far"}}}
+		],
+		"prefabs": {"bullet": {"tags": ["projectile"]}}
+	}`
+
+	ids, err := LoadScene(strings.NewReader(input), world)
+	if err == nil || !strings.Contains(err.Error(), "entities[1].components.position") {
+		t.Errorf("Expected error naming entities[1].components.position, got %v", err)
+	}
+	if ids != nil {
+		t.Errorf("Expected no IDs after a failed load, got %v", ids)
+	}
+	if world.EntityCount() != 0 {
+		t.Errorf("Expected the created entities to be destroyed, got %d alive", world.EntityCount())
+	}
+	if _, ok := world.Prefab("bullet"); ok {
+		t.Error("Prefabs should not be defined after a failed load")
+	}
+}
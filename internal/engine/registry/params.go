@@ -0,0 +1,304 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+)
+
+// ParamType is the type of a block parameter as seen in JSON.
+type ParamType string
+
+const (
+	String ParamType = "string"
+	Number ParamType = "number" // float64
+	Int    ParamType = "int"
+	Bool   ParamType = "bool"
+	Object ParamType = "object" // map[string]any, e.g. an event payload
+	Scalar ParamType = "scalar" // float64, string or bool, e.g. a field value
+
+	// Condition and Conditions hold nested condition blocks,
+	// {"type": "...", "params": {...}}, built through the same registry.
+	// They are used by combinators such as "any" and "not".
+	Condition  ParamType = "condition"
+	Conditions ParamType = "conditions"
+)
+
+// ParamSpec describes one parameter of a condition or action.
+// Min and Max only apply to Number and Int parameters.
+type ParamSpec struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Description string    `json:"description,omitempty"`
+	Required    bool      `json:"required,omitempty"`
+	Default     any       `json:"default,omitempty"`
+	Min         *float64  `json:"min,omitempty"`
+	Max         *float64  `json:"max,omitempty"`
+	Options     []string  `json:"options,omitempty"` // allowed values for String
+}
+
+// Limit returns a pointer to v, for ParamSpec.Min and ParamSpec.Max.
+func Limit(v float64) *float64 {
+	return &v
+}
+
+// Params are the validated parameters handed to a block constructor.
+// Every parameter in the schema is present with its declared Go type
+// (string, float64, int, bool, map[string]any, behavior.Condition or
+// []behavior.Condition; any of the first, second and fourth for Scalar),
+// filled from Default when omitted.
+type Params map[string]any
+
+// String returns a String parameter.
+func (p Params) String(key string) string {
+	s, _ := p[key].(string)
+	return s
+}
+
+// Float returns a Number parameter.
+func (p Params) Float(key string) float64 {
+	f, _ := p[key].(float64)
+	return f
+}
+
+// Int returns an Int parameter.
+func (p Params) Int(key string) int {
+	i, _ := p[key].(int)
+	return i
+}
+
+// Bool returns a Bool parameter.
+func (p Params) Bool(key string) bool {
+	b, _ := p[key].(bool)
+	return b
+}
+
+// Object returns an Object parameter.
+func (p Params) Object(key string) map[string]any {
+	m, _ := p[key].(map[string]any)
+	return m
+}
+
+// Scalar returns a Scalar parameter: a float64, string or bool, or nil if it
+// was omitted.
+func (p Params) Scalar(key string) any {
+	return p[key]
+}
+
+// Condition returns a Condition parameter, nil if it was omitted.
+func (p Params) Condition(key string) behavior.Condition {
+	c, _ := p[key].(behavior.Condition)
+	return c
+}
+
+// Conditions returns a Conditions parameter.
+func (p Params) Conditions(key string) []behavior.Condition {
+	c, _ := p[key].([]behavior.Condition)
+	return c
+}
+
+// mustBeValidSchema panics on schemas that could never bind, such as a
+// default of the wrong type. These are programming errors in Go code.
+func mustBeValidSchema(kind, name string, specs []ParamSpec) {
+	seen := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if seen[spec.Name] {
+			panic(fmt.Sprintf("registry: %s %q declares parameter %q twice", kind, name, spec.Name))
+		}
+		seen[spec.Name] = true
+		if spec.Default != nil {
+			if spec.Type == Condition || spec.Type == Conditions {
+				panic(fmt.Sprintf("registry: %s %q: %s parameter %q cannot have a default", kind, name, spec.Type, spec.Name))
+			}
+			if _, err := (*Registry)(nil).bindParam(spec, spec.Default); err != nil {
+				panic(fmt.Sprintf("registry: %s %q: bad default for %q: %v", kind, name, spec.Name, err))
+			}
+		}
+	}
+}
+
+// bindParams checks raw against the schema, applies defaults and converts
+// values to their declared types. Nested conditions are built through r.
+func (r *Registry) bindParams(specs []ParamSpec, raw map[string]any) (Params, error) {
+	for key := range raw {
+		if !slices.ContainsFunc(specs, func(s ParamSpec) bool { return s.Name == key }) {
+			return nil, &ParamError{Param: key, Err: errors.New("unknown parameter")}
+		}
+	}
+
+	p := make(Params, len(specs))
+	for _, spec := range specs {
+		v, ok := raw[spec.Name]
+		if !ok || v == nil {
+			if spec.Required {
+				return nil, &ParamError{Param: spec.Name, Err: errors.New("required")}
+			}
+			if spec.Default == nil {
+				// The zero value is already typed and need not satisfy
+				// Options or Min/Max.
+				p[spec.Name] = zeroValue(spec.Type)
+				continue
+			}
+			v = spec.Default
+		}
+		bound, err := r.bindParam(spec, v)
+		if err != nil {
+			return nil, nestParamError(spec.Name, err)
+		}
+		p[spec.Name] = bound
+	}
+	return p, nil
+}
+
+// bindParam converts a single value, accepting any Go numeric type for
+// numbers since JSON decoding yields float64.
+func (r *Registry) bindParam(spec ParamSpec, v any) (any, error) {
+	switch spec.Type {
+	case String:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", v)
+		}
+		if len(spec.Options) > 0 && !slices.Contains(spec.Options, s) {
+			return nil, fmt.Errorf("must be one of %v, got %q", spec.Options, s)
+		}
+		return s, nil
+
+	case Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool, got %T", v)
+		}
+		return b, nil
+
+	case Scalar:
+		switch v.(type) {
+		case string, bool:
+			return v, nil
+		}
+		if f, ok := core.ToFloat(v); ok {
+			return f, nil
+		}
+		return nil, fmt.Errorf("expected number, string or bool, got %T", v)
+
+	case Object:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected object, got %T", v)
+		}
+		return m, nil
+
+	case Number, Int:
+		f, ok := core.ToFloat(v)
+		if !ok {
+			return nil, fmt.Errorf("expected %s, got %T", spec.Type, v)
+		}
+		if spec.Min != nil && f < *spec.Min {
+			return nil, fmt.Errorf("must be >= %v, got %v", *spec.Min, f)
+		}
+		if spec.Max != nil && f > *spec.Max {
+			return nil, fmt.Errorf("must be <= %v, got %v", *spec.Max, f)
+		}
+		if spec.Type == Number {
+			return f, nil
+		}
+		n, ok := core.ToInt(v)
+		if !ok {
+			return nil, fmt.Errorf("expected int, got %v", f)
+		}
+		return n, nil
+
+	case Condition:
+		return r.bindCondition(v)
+
+	case Conditions:
+		if list, ok := v.([]behavior.Condition); ok {
+			return list, nil
+		}
+		raw, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected list of conditions, got %T", v)
+		}
+		list := make([]behavior.Condition, 0, len(raw))
+		for i, item := range raw {
+			cond, err := r.bindCondition(item)
+			if err != nil {
+				return nil, nestParamError(fmt.Sprintf("[%d]", i), err)
+			}
+			list = append(list, cond)
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unsupported parameter type %q", spec.Type)
+}
+
+// bindCondition builds a nested {"type": ..., "params": {...}} condition.
+// Go callers may pass a ready-made behavior.Condition instead.
+func (r *Registry) bindCondition(v any) (behavior.Condition, error) {
+	if cond, ok := v.(behavior.Condition); ok {
+		return cond, nil
+	}
+	block, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected condition, got %T", v)
+	}
+	for key := range block {
+		if key != "type" && key != "params" {
+			return nil, &ParamError{Param: key, Err: errors.New("unknown field")}
+		}
+	}
+	name, _ := block["type"].(string)
+	if name == "" {
+		return nil, &ParamError{Param: "type", Err: errors.New("required")}
+	}
+	params, ok := block["params"].(map[string]any)
+	if !ok && block["params"] != nil {
+		return nil, &ParamError{Param: "params", Err: fmt.Errorf("expected object, got %T", block["params"])}
+	}
+
+	cond, err := r.NewCondition(name, params)
+	var ue *UnknownError
+	var pe *ParamError
+	switch {
+	case errors.As(err, &pe):
+		return nil, nestParamError("params", err)
+	case errors.As(err, &ue):
+		return nil, &ParamError{Param: "type", Err: err}
+	}
+	return cond, err
+}
+
+// nestParamError prefixes the parameter path of err with name, so nested
+// problems point at the exact field, e.g. "conditions[1].params.expr".
+func nestParamError(name string, err error) error {
+	pe, ok := err.(*ParamError)
+	if !ok {
+		return &ParamError{Param: name, Err: err}
+	}
+	if strings.HasPrefix(pe.Param, "[") {
+		return &ParamError{Param: name + pe.Param, Err: pe.Err}
+	}
+	return &ParamError{Param: name + "." + pe.Param, Err: pe.Err}
+}
+
+func zeroValue(t ParamType) any {
+	switch t {
+	case String:
+		return ""
+	case Number:
+		return 0.0
+	case Int:
+		return 0
+	case Bool:
+		return false
+	case Object:
+		return map[string]any(nil)
+	case Conditions:
+		return []behavior.Condition(nil)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Package registry maps the condition and action names used in rule files
+// to constructors for the corresponding behavior blocks, together with a
+// typed schema of their parameters. The loader uses it to instantiate blocks
+// from JSON and the editor uses it to list the available blocks and fields.
+package registry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+)
+
+// ConditionSpec describes a condition type.
+type ConditionSpec struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Params      []ParamSpec `json:"params,omitempty"`
+
+	// New builds the condition from validated parameters.
+	New func(p Params) (behavior.Condition, error) `json:"-"`
+}
+
+// ActionSpec describes an action type.
+type ActionSpec struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Params      []ParamSpec `json:"params,omitempty"`
+
+	// New builds the action from validated parameters.
+	New func(p Params) (behavior.Action, error) `json:"-"`
+}
+
+// Registry holds the known condition and action types by name.
+//
+// Usage:
+//
+//	r := registry.New()
+//	r.RegisterAction(registry.ActionSpec{
+//		Name:   "debug_log",
+//		Params: []registry.ParamSpec{{Name: "message", Type: registry.String}},
+//		New: func(p registry.Params) (behavior.Action, error) {
+//			return &actions.DebugLog{Message: p.String("message")}, nil
+//		},
+//	})
+type Registry struct {
+	conditions map[string]ConditionSpec
+	actions    map[string]ActionSpec
+}
+
+// New creates an empty Registry.
+func New() *Registry {
+	return &Registry{
+		conditions: make(map[string]ConditionSpec),
+		actions:    make(map[string]ActionSpec),
+	}
+}
+
+// RegisterCondition adds a condition type. Registering a name twice or an
+// invalid schema panics.
+func (r *Registry) RegisterCondition(spec ConditionSpec) {
+	if _, exists := r.conditions[spec.Name]; exists {
+		panic(fmt.Sprintf("registry: condition %q registered twice", spec.Name))
+	}
+	mustBeValidSchema("condition", spec.Name, spec.Params)
+	r.conditions[spec.Name] = spec
+}
+
+// RegisterAction adds an action type. Registering a name twice or an
+// invalid schema panics.
+func (r *Registry) RegisterAction(spec ActionSpec) {
+	if _, exists := r.actions[spec.Name]; exists {
+		panic(fmt.Sprintf("registry: action %q registered twice", spec.Name))
+	}
+	mustBeValidSchema("action", spec.Name, spec.Params)
+	r.actions[spec.Name] = spec
+}
+
+// NewCondition validates raw parameters against the schema of the condition
+// registered under name and builds it.
+func (r *Registry) NewCondition(name string, raw map[string]any) (behavior.Condition, error) {
+	spec, ok := r.conditions[name]
+	if !ok {
+		return nil, &UnknownError{Kind: "condition", Name: name}
+	}
+	p, err := r.bindParams(spec.Params, raw)
+	if err != nil {
+		return nil, err
+	}
+	return spec.New(p)
+}
+
+// NewAction validates raw parameters against the schema of the action
+// registered under name and builds it.
+func (r *Registry) NewAction(name string, raw map[string]any) (behavior.Action, error) {
+	spec, ok := r.actions[name]
+	if !ok {
+		return nil, &UnknownError{Kind: "action", Name: name}
+	}
+	p, err := r.bindParams(spec.Params, raw)
+	if err != nil {
+		return nil, err
+	}
+	return spec.New(p)
+}
+
+// Condition returns the spec registered under name.
+func (r *Registry) Condition(name string) (ConditionSpec, bool) {
+	spec, ok := r.conditions[name]
+	return spec, ok
+}
+
+// Action returns the spec registered under name.
+func (r *Registry) Action(name string) (ActionSpec, bool) {
+	spec, ok := r.actions[name]
+	return spec, ok
+}
+
+// Conditions lists every registered condition, sorted by name.
+func (r *Registry) Conditions() []ConditionSpec {
+	specs := make([]ConditionSpec, 0, len(r.conditions))
+	for _, spec := range r.conditions {
+		specs = append(specs, spec)
+	}
+	slices.SortFunc(specs, func(a, b ConditionSpec) int { return strings.Compare(a.Name, b.Name) })
+	return specs
+}
+
+// Actions lists every registered action, sorted by name.
+func (r *Registry) Actions() []ActionSpec {
+	specs := make([]ActionSpec, 0, len(r.actions))
+	for _, spec := range r.actions {
+		specs = append(specs, spec)
+	}
+	slices.SortFunc(specs, func(a, b ActionSpec) int { return strings.Compare(a.Name, b.Name) })
+	return specs
+}
+
+// UnknownError reports a condition or action name that is not registered.
+type UnknownError struct {
+	Kind string // "condition" or "action"
+	Name string
+}
+
+func (e *UnknownError) Error() string {
+	return fmt.Sprintf("unknown %s %q", e.Kind, e.Name)
+}
+
+// ParamError reports a problem with a single parameter, so callers can
+// point at the exact field in the source file.
+type ParamError struct {
+	Param string
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	return e.Param + ": " + e.Err.Error()
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,323 @@
+package registry
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/behavior"
+	"github.com/GiannisPettas/ember2D/internal/engine/core"
+)
+
+// Test block types
+type testAction struct {
+	params Params
+}
+
+func (a *testAction) Execute(ctx *core.Context) error { return nil }
+
+type testCondition struct {
+	params Params
+}
+
+func (c *testCondition) Evaluate(ctx *core.Context) bool { return true }
+
+func testRegistry() *Registry {
+	r := New()
+	r.RegisterAction(ActionSpec{
+		Name: "damage",
+		Params: []ParamSpec{
+			{Name: "target", Type: String, Default: "B", Options: []string{"A", "B"}},
+			{Name: "amount", Type: Int, Required: true, Min: Limit(0), Max: Limit(1000)},
+			{Name: "scale", Type: Number, Default: 1.5},
+			{Name: "crit", Type: Bool},
+			{Name: "tags", Type: Object},
+		},
+		New: func(p Params) (behavior.Action, error) {
+			return &testAction{params: p}, nil
+		},
+	})
+	return r
+}
+
+// ============================================
+// Binding Tests
+// ============================================
+
+func TestNewActionAppliesDefaultsAndTypes(t *testing.T) {
+	r := testRegistry()
+
+	act, err := r.NewAction("damage", map[string]any{"amount": 20.0})
+	if err != nil {
+		t.Fatalf("NewAction failed: %v", err)
+	}
+	p := act.(*testAction).params
+
+	if p.Int("amount") != 20 {
+		t.Errorf("Expected amount=20, got %v", p["amount"])
+	}
+	if p.String("target") != "B" {
+		t.Errorf("Expected default target=B, got %v", p["target"])
+	}
+	if p.Float("scale") != 1.5 {
+		t.Errorf("Expected default scale=1.5, got %v", p["scale"])
+	}
+	if p.Bool("crit") {
+		t.Error("Expected zero-value crit=false")
+	}
+	if p.Object("tags") != nil {
+		t.Errorf("Expected zero-value tags=nil, got %v", p["tags"])
+	}
+
+	act, _ = r.NewAction("damage", map[string]any{"amount": 1.0, "tags": map[string]any{"fire": true}})
+	if tags := act.(*testAction).params.Object("tags"); tags["fire"] != true {
+		t.Errorf("Expected tags to be bound, got %v", tags)
+	}
+}
+
+func TestNewActionParamErrors(t *testing.T) {
+	r := testRegistry()
+	cases := []struct {
+		raw   map[string]any
+		param string
+	}{
+		{map[string]any{}, "amount"},                             // required
+		{map[string]any{"amount": "lots"}, "amount"},             // wrong type
+		{map[string]any{"amount": 2.5}, "amount"},                // not an int
+		{map[string]any{"amount": -1.0}, "amount"},               // below Min
+		{map[string]any{"amount": 5000.0}, "amount"},             // above Max
+		{map[string]any{"amount": 1.0, "target": "C"}, "target"}, // not an option
+		{map[string]any{"amount": 1.0, "crit": "yes"}, "crit"},   // wrong type
+		{map[string]any{"amount": 1.0, "tags": "fire"}, "tags"},  // wrong type
+		{map[string]any{"amount": 1.0, "color": "red"}, "color"}, // unknown
+	}
+	for _, tc := range cases {
+		_, err := r.NewAction("damage", tc.raw)
+		var pe *ParamError
+		if !errors.As(err, &pe) {
+			t.Errorf("%v: expected a *ParamError, got %v", tc.raw, err)
+			continue
+		}
+		if pe.Param != tc.param {
+			t.Errorf("%v: expected error for %q, got %q", tc.raw, tc.param, pe.Param)
+		}
+	}
+}
+
+func TestNumberParamsAcceptAnyGoNumber(t *testing.T) {
+	type hitPoints uint16
+	r := testRegistry()
+	for _, amount := range []any{int8(20), uint8(20), int16(20), hitPoints(20), float32(20)} {
+		act, err := r.NewAction("damage", map[string]any{"amount": amount})
+		if err != nil {
+			t.Errorf("amount %T: %v", amount, err)
+			continue
+		}
+		if got := act.(*testAction).params.Int("amount"); got != 20 {
+			t.Errorf("amount %T: expected 20, got %d", amount, got)
+		}
+	}
+}
+
+func TestIntParamsRejectOutOfRange(t *testing.T) {
+	r := New()
+	r.RegisterAction(ActionSpec{
+		Name:   "repeat",
+		Params: []ParamSpec{{Name: "n", Type: Int, Required: true}},
+		New: func(p Params) (behavior.Action, error) {
+			return &testAction{params: p}, nil
+		},
+	})
+
+	for _, n := range []any{1e30, -1e19, math.Inf(1), math.NaN(), 0.5} {
+		if _, err := r.NewAction("repeat", map[string]any{"n": n}); err == nil {
+			t.Errorf("n = %v should be rejected", n)
+		}
+	}
+	act, err := r.NewAction("repeat", map[string]any{"n": 1e9})
+	if err != nil || act.(*testAction).params.Int("n") != 1e9 {
+		t.Errorf("n = 1e9 should bind, got %v", err)
+	}
+}
+
+func TestScalarParams(t *testing.T) {
+	r := New()
+	r.RegisterAction(ActionSpec{
+		Name:   "set",
+		Params: []ParamSpec{{Name: "value", Type: Scalar, Required: true}},
+		New: func(p Params) (behavior.Action, error) {
+			return &testAction{params: p}, nil
+		},
+	})
+
+	for _, c := range []struct{ in, want any }{{"calm", "calm"}, {true, true}, {2.5, 2.5}, {uint8(7), 7.0}} {
+		act, err := r.NewAction("set", map[string]any{"value": c.in})
+		if err != nil {
+			t.Errorf("value %v: %v", c.in, err)
+			continue
+		}
+		if got := act.(*testAction).params.Scalar("value"); got != c.want {
+			t.Errorf("value %v: expected %#v, got %#v", c.in, c.want, got)
+		}
+	}
+	if _, err := r.NewAction("set", map[string]any{"value": []any{1.0}}); err == nil {
+		t.Error("A list should not bind as a scalar")
+	}
+}
+
+func TestOmittedOptionalParamSkipsChecks(t *testing.T) {
+	r := New()
+	r.RegisterAction(ActionSpec{
+		Name: "move",
+		Params: []ParamSpec{
+			{Name: "mode", Type: String, Options: []string{"a", "b"}},
+			{Name: "speed", Type: Number, Min: Limit(1)},
+		},
+		New: func(p Params) (behavior.Action, error) {
+			return &testAction{params: p}, nil
+		},
+	})
+
+	act, err := r.NewAction("move", nil)
+	if err != nil {
+		t.Fatalf("Omitted optional params should bind, got %v", err)
+	}
+	p := act.(*testAction).params
+	if p["mode"] != "" || p["speed"] != 0.0 {
+		t.Errorf("Expected zero values, got %v", p)
+	}
+	if _, err := r.NewAction("move", map[string]any{"mode": "c"}); err == nil {
+		t.Error("A given value should still be checked against Options")
+	}
+}
+
+func TestNewActionUnknown(t *testing.T) {
+	_, err := testRegistry().NewAction("explode", nil)
+
+	var ue *UnknownError
+	if !errors.As(err, &ue) || ue.Name != "explode" {
+		t.Errorf("Expected UnknownError for explode, got %v", err)
+	}
+}
+
+// ============================================
+// Nested Condition Tests
+// ============================================
+
+// nestingRegistry has "any" (a list), "not" (one condition) and "above" (a
+// leaf with a required number).
+func nestingRegistry() *Registry {
+	r := New()
+	newCond := func(p Params) (behavior.Condition, error) { return &testCondition{params: p}, nil }
+	r.RegisterCondition(ConditionSpec{Name: "any", Params: []ParamSpec{{Name: "conditions", Type: Conditions, Required: true}}, New: newCond})
+	r.RegisterCondition(ConditionSpec{Name: "not", Params: []ParamSpec{{Name: "condition", Type: Condition}}, New: newCond})
+	r.RegisterCondition(ConditionSpec{Name: "above", Params: []ParamSpec{{Name: "n", Type: Number, Required: true}}, New: newCond})
+	return r
+}
+
+func TestNestedConditions(t *testing.T) {
+	r := nestingRegistry()
+
+	cond, err := r.NewCondition("any", map[string]any{"conditions": []any{
+		map[string]any{"type": "above", "params": map[string]any{"n": 1.0}},
+		map[string]any{"type": "not", "params": map[string]any{"condition": map[string]any{"type": "above", "params": map[string]any{"n": 2.0}}}},
+	}})
+	if err != nil {
+		t.Fatalf("NewCondition failed: %v", err)
+	}
+
+	list := cond.(*testCondition).params.Conditions("conditions")
+	if len(list) != 2 {
+		t.Fatalf("Expected 2 nested conditions, got %d", len(list))
+	}
+	inner := list[1].(*testCondition).params.Condition("condition")
+	if inner.(*testCondition).params.Float("n") != 2 {
+		t.Error("Doubly nested condition lost its params")
+	}
+
+	omitted, err := r.NewCondition("not", nil)
+	if err != nil || omitted.(*testCondition).params.Condition("condition") != nil {
+		t.Errorf("Omitted optional condition should be nil, got %v", err)
+	}
+}
+
+func TestNestedConditionErrors(t *testing.T) {
+	r := nestingRegistry()
+	above := func(params map[string]any) map[string]any {
+		return map[string]any{"type": "above", "params": params}
+	}
+	cases := []struct {
+		raw   map[string]any
+		param string
+		msg   string
+	}{
+		{map[string]any{"conditions": "above"}, "conditions", "expected list of conditions, got string"},
+		{map[string]any{"conditions": []any{above(map[string]any{"n": 1.0}), above(nil)}}, "conditions[1].params.n", "required"},
+		{map[string]any{"conditions": []any{map[string]any{"type": "nope"}}}, "conditions[0].type", `unknown condition "nope"`},
+		{map[string]any{"conditions": []any{map[string]any{"params": map[string]any{}}}}, "conditions[0].type", "required"},
+		{map[string]any{"conditions": []any{map[string]any{"type": "above", "extra": 1}}}, "conditions[0].extra", "unknown field"},
+		{map[string]any{"conditions": []any{map[string]any{"type": "not", "params": map[string]any{"condition": above(map[string]any{"n": "x"})}}}}, "conditions[0].params.condition.params.n", "expected number, got string"},
+	}
+	for _, tc := range cases {
+		_, err := r.NewCondition("any", tc.raw)
+		var pe *ParamError
+		if !errors.As(err, &pe) {
+			t.Errorf("%v: expected a *ParamError, got %v", tc.raw, err)
+			continue
+		}
+		if pe.Param != tc.param || pe.Err.Error() != tc.msg {
+			t.Errorf("Expected %s: %s, got %s: %v", tc.param, tc.msg, pe.Param, pe.Err)
+		}
+	}
+}
+
+func TestConditionDefaultPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("A nested condition parameter with a default should panic")
+		}
+	}()
+	New().RegisterCondition(ConditionSpec{
+		Name:   "broken",
+		Params: []ParamSpec{{Name: "c", Type: Condition, Default: map[string]any{"type": "x"}}},
+	})
+}
+
+// ============================================
+// Registration Tests
+// ============================================
+
+func TestActionsListedByName(t *testing.T) {
+	r := testRegistry()
+	r.RegisterAction(ActionSpec{Name: "a_first", New: func(p Params) (behavior.Action, error) { return nil, nil }})
+
+	specs := r.Actions()
+	if len(specs) != 2 || specs[0].Name != "a_first" || specs[1].Name != "damage" {
+		t.Errorf("Expected [a_first damage], got %v", specs)
+	}
+	if len(specs[1].Params) != 5 {
+		t.Errorf("Expected damage to expose 5 params, got %d", len(specs[1].Params))
+	}
+}
+
+func TestRegisterBadDefaultPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("A default of the wrong type should panic")
+		}
+	}()
+	New().RegisterCondition(ConditionSpec{
+		Name:   "broken",
+		Params: []ParamSpec{{Name: "n", Type: Int, Default: "ten"}},
+	})
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	r := testRegistry()
+	defer func() {
+		if recover() == nil {
+			t.Error("Registering an action twice should panic")
+		}
+	}()
+	r.RegisterAction(ActionSpec{Name: "damage"})
+}
@@ -0,0 +1,227 @@
+// Package schema generates JSON Schema documents for ember2D's config files,
+// so editors and CI can validate rules.json and level.json before they reach
+// the runtime. Rule schemas come from the condition/action registry; scene
+// schemas come from the component types registered in a World.
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+	"github.com/GiannisPettas/ember2D/internal/engine/loader"
+	"github.com/GiannisPettas/ember2D/internal/engine/registry"
+)
+
+// Draft is the JSON Schema dialect of the generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document. Encoding it with encoding/json yields
+// sorted keys, so output is stable across runs.
+type Schema map[string]any
+
+// TypedComponent is a registered component store that reports its Go type,
+// from which Scene derives the component's schema.
+type TypedComponent interface {
+	entity.ComponentStore
+	Type() reflect.Type
+}
+
+// Rules returns the schema of a rules.json file whose conditions and actions
+// are the blocks registered in reg.
+func Rules(reg *registry.Registry) Schema {
+	conditions := make([]any, 0)
+	for _, spec := range reg.Conditions() {
+		conditions = append(conditions, blockSchema(spec.Name, spec.Description, spec.Params))
+	}
+	actions := make([]any, 0)
+	for _, spec := range reg.Actions() {
+		actions = append(actions, blockSchema(spec.Name, spec.Description, spec.Params))
+	}
+
+	doc := FromType(reflect.TypeFor[loader.RuleFile]())
+	rule := doc["properties"].(Schema)["rules"].(Schema)["items"].(Schema)
+	rule["required"] = []string{"id", "trigger", "actions"}
+	rule["properties"].(Schema)["conditions"] = Schema{"type": "array", "items": Schema{"$ref": "#/$defs/condition"}}
+	rule["properties"].(Schema)["actions"] = Schema{"type": "array", "minItems": 1, "items": Schema{"$ref": "#/$defs/action"}}
+	trigger := rule["properties"].(Schema)["trigger"].(Schema)
+	trigger["required"] = []string{"type"}
+	trigger["properties"].(Schema)["until"] = Schema{"$ref": "#/$defs/condition"}
+
+	doc["$schema"] = Draft
+	doc["title"] = "ember2D rules"
+	doc["required"] = []string{"rules"}
+	doc["$defs"] = Schema{
+		"condition": Schema{"oneOf": conditions},
+		"action":    Schema{"oneOf": actions},
+	}
+	return doc
+}
+
+// blockSchema describes {"type": name, "params": {...}} for one block.
+func blockSchema(name, description string, params []registry.ParamSpec) Schema {
+	props := Schema{}
+	required := make([]string, 0)
+	for _, p := range params {
+		props[p.Name] = paramSchema(p)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+
+	paramsSchema := Schema{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		paramsSchema["required"] = required
+	}
+
+	block := Schema{
+		"type": "object",
+		"properties": Schema{
+			"type":   Schema{"const": name},
+			"params": paramsSchema,
+		},
+		"required":             []string{"type"},
+		"additionalProperties": false,
+	}
+	if description != "" {
+		block["description"] = description
+	}
+	if len(required) > 0 {
+		block["required"] = []string{"type", "params"}
+	}
+	return block
+}
+
+func paramSchema(p registry.ParamSpec) Schema {
+	s := Schema{}
+	switch p.Type {
+	case registry.String:
+		s["type"] = "string"
+		if len(p.Options) > 0 {
+			s["enum"] = p.Options
+		}
+	case registry.Number:
+		s["type"] = "number"
+	case registry.Int:
+		s["type"] = "integer"
+	case registry.Bool:
+		s["type"] = "boolean"
+	case registry.Object:
+		s["type"] = "object"
+	case registry.Scalar:
+		s["type"] = []string{"number", "string", "boolean"}
+	case registry.Condition:
+		s["$ref"] = "#/$defs/condition"
+	case registry.Conditions:
+		s["type"] = "array"
+		s["items"] = Schema{"$ref": "#/$defs/condition"}
+	}
+	if p.Min != nil {
+		s["minimum"] = *p.Min
+	}
+	if p.Max != nil {
+		s["maximum"] = *p.Max
+	}
+	if p.Default != nil {
+		s["default"] = p.Default
+	}
+	if p.Description != "" {
+		s["description"] = p.Description
+	}
+	return s
+}
+
+// Scene returns the schema of a level.json file for the components
+// registered in world. Every registered component must implement
+// TypedComponent.
+func Scene(world *entity.World) (Schema, error) {
+	comps := Schema{}
+	for _, name := range world.ComponentNames() {
+		store, ok := world.Component(name).(TypedComponent)
+		if !ok {
+			return nil, fmt.Errorf("schema: component %q does not report its type", name)
+		}
+		comps[name] = FromType(store.Type())
+	}
+
+	doc := FromType(reflect.TypeFor[loader.Scene]())
+	ent := doc["properties"].(Schema)["entities"].(Schema)["items"].(Schema)
+	ent["required"] = []string{"id"}
+	ent["properties"].(Schema)["components"] = Schema{
+		"type":                 "object",
+		"properties":           comps,
+		"additionalProperties": false,
+	}
+	prefab := doc["properties"].(Schema)["prefabs"].(Schema)["additionalProperties"].(Schema)
+	prefab["properties"].(Schema)["components"] = ent["properties"].(Schema)["components"]
+
+	doc["$schema"] = Draft
+	doc["title"] = "ember2D scene"
+	doc["required"] = []string{"entities"}
+	return doc, nil
+}
+
+// FromType derives a schema from a Go type the way encoding/json would
+// encode it. Structs become closed objects since the loaders reject unknown
+// fields.
+func FromType(t reflect.Type) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "integer"}
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Uint8:
+		return Schema{"type": "integer", "minimum": 0, "maximum": 255}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return Schema{} // json.RawMessage and []byte hold arbitrary data
+		}
+		return Schema{"type": "array", "items": FromType(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": FromType(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	return Schema{}
+}
+
+func structSchema(t reflect.Type) Schema {
+	props := Schema{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		props[name] = FromType(f.Type)
+	}
+	return Schema{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/GiannisPettas/ember2D/internal/engine/components"
+	"github.com/GiannisPettas/ember2D/internal/engine/entity"
+	"github.com/GiannisPettas/ember2D/internal/engine/loader"
+)
+
+// lookup walks nested schema objects by key.
+func lookup(t *testing.T, s Schema, keys ...string) Schema {
+	t.Helper()
+	for _, k := range keys {
+		next, ok := s[k].(Schema)
+		if !ok {
+			t.Fatalf("schema has no object at %v (stopped at %q)", keys, k)
+		}
+		s = next
+	}
+	return s
+}
+
+// ============================================
+// Rules Schema Tests
+// ============================================
+
+func TestRulesSchemaListsBlocks(t *testing.T) {
+	doc := Rules(loader.DefaultRegistry())
+
+	if doc["$schema"] != Draft {
+		t.Errorf("Expected $schema %q, got %v", Draft, doc["$schema"])
+	}
+
+	actions := lookup(t, doc, "$defs", "action")["oneOf"].([]any)
+	found := false
+	for _, a := range actions {
+		block := a.(Schema)
+		if lookup(t, block, "properties", "type")["const"] == "debug_log" {
+			found = true
+			msg := lookup(t, block, "properties", "params", "properties", "message")
+			if msg["type"] != "string" {
+				t.Errorf("Expected message to be a string, got %v", msg["type"])
+			}
+		}
+		if lookup(t, block, "properties", "type")["const"] == "set_field" {
+			value := lookup(t, block, "properties", "params", "properties", "value")
+			if types, _ := value["type"].([]string); !slices.Equal(types, []string{"number", "string", "boolean"}) {
+				t.Errorf("Expected set_field.value to allow number, string or boolean, got %v", value["type"])
+			}
+		}
+	}
+	if !found {
+		t.Error("debug_log missing from action schemas")
+	}
+
+	for _, c := range lookup(t, doc, "$defs", "condition")["oneOf"].([]any) {
+		block := c.(Schema)
+		if lookup(t, block, "properties", "type")["const"] != "any" {
+			continue
+		}
+		items := lookup(t, block, "properties", "params", "properties", "conditions", "items")
+		if items["$ref"] != "#/$defs/condition" {
+			t.Errorf("any.conditions should reference the condition definitions, got %v", items)
+		}
+	}
+
+	trigger := lookup(t, doc, "properties", "rules", "items", "properties", "trigger")
+	if lookup(t, trigger, "properties", "interval")["type"] != "integer" {
+		t.Error("trigger.interval should be an integer")
+	}
+	if lookup(t, trigger, "properties", "until")["$ref"] != "#/$defs/condition" {
+		t.Error("trigger.until should reference the condition definitions")
+	}
+}
+
+func TestRulesSchemaIsStable(t *testing.T) {
+	first, err := json.Marshal(Rules(loader.DefaultRegistry()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		again, _ := json.Marshal(Rules(loader.DefaultRegistry()))
+		if string(again) != string(first) {
+			t.Fatal("Rules schema differs between runs")
+		}
+	}
+}
+
+// ============================================
+// Scene Schema Tests
+// ============================================
+
+func TestSceneSchemaFromComponents(t *testing.T) {
+	world := entity.NewWorld()
+	components.RegisterBuiltins(world)
+
+	doc, err := Scene(world)
+	if err != nil {
+		t.Fatalf("Scene failed: %v", err)
+	}
+
+	comps := lookup(t, doc, "properties", "entities", "items", "properties", "components")
+	if comps["additionalProperties"] != false {
+		t.Error("Unknown components should be rejected")
+	}
+	pos := lookup(t, comps, "properties", "position")
+	if lookup(t, pos, "properties", "x")["type"] != "number" {
+		t.Error("position.x should be a number")
+	}
+	r := lookup(t, comps, "properties", "display", "properties", "r")
+	if r["type"] != "integer" || r["maximum"] != 255 {
+		t.Errorf("display.r should be an integer up to 255, got %v", r)
+	}
+	prefab := lookup(t, doc, "properties", "prefabs", "additionalProperties", "properties", "components")
+	if lookup(t, prefab, "properties", "position")["type"] != "object" {
+		t.Error("Prefab components should use the component schemas")
+	}
+}
+
+func TestSceneSchemaRejectsUntypedComponent(t *testing.T) {
+	world := entity.NewWorld()
+	world.RegisterComponent("opaque", untypedStore{})
+
+	if _, err := Scene(world); err == nil {
+		t.Error("Expected an error for a component without type information")
+	}
+}
+
+type untypedStore struct{}
+
+func (untypedStore) Has(e entity.Entity) bool { return false }
+func (untypedStore) Remove(e entity.Entity)   {}
+func (untypedStore) Count() int               { return 0 }
@@ -0,0 +1,279 @@
+// Package json holds the JSON helpers shared by the engine's file loaders.
+// Import it as jsonutil to avoid clashing with encoding/json.
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Error is a decoding error annotated with where it happened.
+// File, Line, Col and Path are left empty/zero when unknown.
+type Error struct {
+	File string
+	Line int
+	Col  int
+	Path string // field path, e.g. "rules[2].trigger"
+	Err  error
+}
+
+func (e *Error) Error() string {
+	prefix := e.File
+	if e.Line > 0 {
+		if prefix != "" {
+			prefix += ":"
+		}
+		prefix += fmt.Sprintf("%d:%d", e.Line, e.Col)
+	}
+	msg := e.Err.Error()
+	if e.Path != "" {
+		msg = e.Path + ": " + msg
+	}
+	if prefix == "" {
+		return msg
+	}
+	return prefix + ": " + msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DecodeStrict decodes exactly one JSON value from r into v, rejecting
+// unknown object fields and trailing data. Syntax errors carry the line and
+// column they occurred at; type errors and unknown fields also carry the
+// field path.
+func DecodeStrict(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return UnmarshalStrict(data, v)
+}
+
+// UnmarshalStrict is DecodeStrict for an in-memory document.
+func UnmarshalStrict(data []byte, v any) error {
+	dec := stdjson.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return annotate(data, err, reflect.TypeOf(v))
+	}
+	if dec.More() {
+		line, col := Position(data, dec.InputOffset())
+		return &Error{Line: line, Col: col, Err: errors.New("unexpected data after top-level value")}
+	}
+	return nil
+}
+
+// DecodeFile reads and strictly decodes the JSON file at path into v.
+// Errors read like "level.json:12:5: ...".
+func DecodeFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := UnmarshalStrict(data, v); err != nil {
+		var jsonErr *Error
+		if errors.As(err, &jsonErr) {
+			jsonErr.File = path
+			return jsonErr
+		}
+		return &Error{File: path, Err: err}
+	}
+	return nil
+}
+
+// EncodeIndent writes v to w as indented, human-readable JSON.
+func EncodeIndent(w io.Writer, v any) error {
+	enc := stdjson.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Position converts a byte offset into a 1-based line and column.
+func Position(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, col = 1, 1
+	for _, c := range data[:offset] {
+		if c == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+// annotate attaches a position to syntax, type and unknown field errors,
+// and a field path to the latter two. t is the type data was decoded into.
+func annotate(data []byte, err error, t reflect.Type) error {
+	var syntaxErr *stdjson.SyntaxError
+	var typeErr *stdjson.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		line, col := Position(data, syntaxErr.Offset)
+		return &Error{Line: line, Col: col, Err: err}
+	case errors.As(err, &typeErr):
+		line, col := Position(data, typeErr.Offset)
+		got := typeErr.Value
+		if i := strings.IndexByte(got, ' '); i >= 0 {
+			got = got[:i] // "number 1.5" -> "number"
+		}
+		return &Error{Line: line, Col: col, Path: fieldPath(typeErr.Field), Err: fmt.Errorf("expected %s, got %s", typeErr.Type, got)}
+	case errors.Is(err, io.EOF):
+		return &Error{Err: errors.New("empty document")}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json reports only the key, so find it again.
+		w := &unknownFinder{dec: stdjson.NewDecoder(bytes.NewReader(data)), data: data}
+		if w.walk(t, "") {
+			line, col := Position(data, w.offset)
+			return &Error{Line: line, Col: col, Path: w.path, Err: fmt.Errorf("unknown field %q", w.key)}
+		}
+	}
+	return &Error{Err: err}
+}
+
+// fieldPath turns encoding/json's "rules.0.trigger" into "rules[0].trigger".
+func fieldPath(field string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil && i > 0 {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+var unmarshalerType = reflect.TypeFor[stdjson.Unmarshaler]()
+
+// unknownFinder walks a document alongside the type it is decoded into and
+// stops at the first object key the type has no field for, the same key
+// encoding/json rejects first.
+type unknownFinder struct {
+	dec    *stdjson.Decoder
+	data   []byte
+	path   string // path of the object holding key
+	key    string
+	offset int64 // where key starts
+}
+
+// walk reads one value of type t (nil for anything) and reports whether an
+// unknown key was found in it.
+func (w *unknownFinder) walk(t reflect.Type, path string) bool {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && (t.Kind() == reflect.Interface || reflect.PointerTo(t).Implements(unmarshalerType)) {
+		t = nil // decoded as a whole, never unknown
+	}
+
+	tok, err := w.dec.Token()
+	if err != nil {
+		return false
+	}
+	switch tok {
+	case stdjson.Delim('{'):
+		for w.dec.More() {
+			offset := w.nextOffset()
+			tok, err := w.dec.Token()
+			if err != nil {
+				return false
+			}
+			key := tok.(string)
+			var elem reflect.Type
+			if t != nil {
+				switch t.Kind() {
+				case reflect.Struct:
+					field, ok := fieldByJSONName(t, key)
+					if !ok {
+						w.path, w.key, w.offset = path, key, offset
+						return true
+					}
+					elem = field
+				case reflect.Map:
+					elem = t.Elem()
+				}
+			}
+			sub := key
+			if path != "" {
+				sub = path + "." + key
+			}
+			if w.walk(elem, sub) {
+				return true
+			}
+		}
+	case stdjson.Delim('['):
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for i := 0; w.dec.More(); i++ {
+			if w.walk(elem, fmt.Sprintf("%s[%d]", path, i)) {
+				return true
+			}
+		}
+	default:
+		return false
+	}
+	w.dec.Token() // closing delimiter
+	return false
+}
+
+// nextOffset returns where the next token starts.
+func (w *unknownFinder) nextOffset() int64 {
+	offset := w.dec.InputOffset()
+	for offset < int64(len(w.data)) && strings.IndexByte(" \t\r\n,", w.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// fieldByJSONName returns the type of the struct field encoding/json would
+// decode key into, matching names case-insensitively like it does.
+func fieldByJSONName(t reflect.Type, key string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if found, ok := fieldByJSONName(ft, key); ok {
+					return found, true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.EqualFold(name, key) {
+			return f.Type, true
+		}
+	}
+	return nil, false
+}
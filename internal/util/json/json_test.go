@@ -0,0 +1,168 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// ============================================
+// Position Tests
+// ============================================
+
+func TestPosition(t *testing.T) {
+	data := []byte("ab\ncd\n\nef")
+	cases := []struct {
+		offset    int64
+		line, col int
+	}{
+		{0, 1, 1},
+		{2, 1, 3},
+		{3, 2, 1},
+		{7, 4, 1},
+		{9, 4, 3},
+		{100, 4, 3}, // clamped to the end
+	}
+	for _, c := range cases {
+		line, col := Position(data, c.offset)
+		if line != c.line || col != c.col {
+			t.Errorf("Position(%d) = %d:%d, want %d:%d", c.offset, line, col, c.line, c.col)
+		}
+	}
+}
+
+// ============================================
+// Decoding Tests
+// ============================================
+
+func TestUnmarshalStrict(t *testing.T) {
+	var p point
+	if err := UnmarshalStrict([]byte(`{"x": 1, "y": 2}`), &p); err != nil {
+		t.Fatalf("UnmarshalStrict failed: %v", err)
+	}
+	if p != (point{X: 1, Y: 2}) {
+		t.Errorf("Expected {1 2}, got %+v", p)
+	}
+}
+
+func TestUnmarshalStrictErrors(t *testing.T) {
+	cases := map[string]struct {
+		input string
+		want  string
+	}{
+		"syntax":        {"{\n  \"x\": 1,\n  \"y\" 2\n}", "3:"},
+		"type":          {"{\n  \"x\": \"one\"\n}", "2:"},
+		"unknown field": {`{"z": 1}`, `unknown field "z"`},
+		"trailing data": {"{\"x\": 1}\n{\"x\": 2}", "2:1: unexpected data after top-level value"},
+		"empty":         {"  ", "empty document"},
+	}
+	for name, c := range cases {
+		var p point
+		err := UnmarshalStrict([]byte(c.input), &p)
+		var jsonErr *Error
+		if !errors.As(err, &jsonErr) {
+			t.Errorf("%s: expected *Error, got %v", name, err)
+			continue
+		}
+		if !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: expected %q in %q", name, c.want, err.Error())
+		}
+	}
+}
+
+type shape struct {
+	Name   string         `json:"name"`
+	Points []point        `json:"points"`
+	Meta   map[string]any `json:"meta"`
+	Anchor *point
+}
+
+func TestUnmarshalStrictFieldPaths(t *testing.T) {
+	cases := map[string]struct {
+		input string
+		want  string
+	}{
+		"unknown nested":   {"{\n  \"points\": [{\"x\": 1}, {\"x\": 2, \"z\": 3}]\n}", `2:33: points[1]: unknown field "z"`},
+		"unknown top":      {`{"name": "a", "nmae": "b"}`, `1:15: unknown field "nmae"`},
+		"unknown pointer":  {`{"anchor": {"y": 1, "w": 2}}`, `1:21: anchor: unknown field "w"`},
+		"free-form map":    {`{"meta": {"z": {"z": 1}}, "points": [{"q": 1}]}`, `1:39: points[0]: unknown field "q"`},
+		"type in slice":    {`{"points": [{"x": 1}, {"y": "two"}]}`, `points[1].y: expected int, got string`},
+		"type with number": {`{"name": 1.5}`, `name: expected string, got number`},
+	}
+	for name, c := range cases {
+		var s shape
+		err := UnmarshalStrict([]byte(c.input), &s)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: expected %q in %v", name, c.want, err)
+		}
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var p point
+	if err := DecodeStrict(strings.NewReader(`{"y": 3}`), &p); err != nil || p.Y != 3 {
+		t.Errorf("DecodeStrict = %+v, %v", p, err)
+	}
+	if err := DecodeStrict(strings.NewReader(`{"y": 3} 4`), &p); err == nil {
+		t.Error("DecodeStrict should reject trailing data")
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "level.json")
+	if err := os.WriteFile(path, []byte("{\n  \"x\": 1,\n  \"y\": true\n}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var p point
+	err := DecodeFile(path, &p)
+	if err == nil || !strings.HasPrefix(err.Error(), path+":3:") {
+		t.Errorf("Expected a %s:3:col prefix, got %v", path, err)
+	}
+
+	if err := DecodeFile(filepath.Join(dir, "missing.json"), &p); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}
+
+func TestErrorFormat(t *testing.T) {
+	base := errors.New("bad")
+	cases := map[string]*Error{
+		"bad":             {Err: base},
+		"a.json: bad":     {File: "a.json", Err: base},
+		"2:5: bad":        {Line: 2, Col: 5, Err: base},
+		"a.json:2:5: bad": {File: "a.json", Line: 2, Col: 5, Err: base},
+	}
+	for want, e := range cases {
+		if e.Error() != want {
+			t.Errorf("Expected %q, got %q", want, e.Error())
+		}
+		if !errors.Is(e, base) {
+			t.Errorf("%q should unwrap to the cause", want)
+		}
+	}
+}
+
+// ============================================
+// Encoding Tests
+// ============================================
+
+func TestEncodeIndent(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeIndent(&buf, point{X: 1, Y: 2}); err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"x\": 1,\n  \"y\": 2\n}\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}